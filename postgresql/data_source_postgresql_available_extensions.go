@@ -0,0 +1,107 @@
+package postgresql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// dataSourcePostgreSQLAvailableExtensions lists the full catalog of
+// extensions the server can install, with their default and available
+// versions. Unlike dataSourcePostgreSQLExtension, it doesn't require the
+// extension to already be installed, which is what lets module authors
+// guard a `postgresql_extension` resource on whether a given extension (or
+// version of it) is actually available on the target cluster before
+// creating it.
+func dataSourcePostgreSQLAvailableExtensions() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourcePostgreSQLAvailableExtensionsRead,
+
+		Schema: map[string]*schema.Schema{
+			extDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The database to list available extensions from. Defaults to the provider's connection database.",
+			},
+			"extensions": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						extNameAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"default_version": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"versions": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+const dataSourceAvailableExtensionsQuery = `
+SELECT a.name, a.default_version, array_agg(v.version ORDER BY v.version)
+FROM pg_catalog.pg_available_extensions a
+JOIN pg_catalog.pg_available_extension_versions v ON v.name = a.name
+GROUP BY a.name, a.default_version
+ORDER BY a.name
+`
+
+func dataSourcePostgreSQLAvailableExtensionsRead(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*Client)
+	c.catalogLock.RLock()
+	defer c.catalogLock.RUnlock()
+
+	database := getDatabaseForExtension(d, c)
+
+	txn, err := startTransaction(c, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	rows, err := txn.Query(dataSourceAvailableExtensionsQuery)
+	if err != nil {
+		return fmt.Errorf("Error reading available extensions: %w", err)
+	}
+	defer rows.Close()
+
+	var extensions []map[string]interface{}
+	for rows.Next() {
+		var name, defaultVersion string
+		var versions []string
+		if err := rows.Scan(&name, &defaultVersion, &versions); err != nil {
+			return fmt.Errorf("Error scanning available extension: %w", err)
+		}
+
+		extensions = append(extensions, map[string]interface{}{
+			extNameAttr:       name,
+			"default_version": defaultVersion,
+			"versions":        versions,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("Error iterating available extensions: %w", err)
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("Error committing transaction: %w", err)
+	}
+
+	d.SetId(strings.Join([]string{database, "available_extensions"}, "/"))
+	if err := d.Set("extensions", extensions); err != nil {
+		return fmt.Errorf("Error setting extensions: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// dataSourcePostgreSQLExtension looks up a single installed extension by
+// name, joining pg_extension with pg_available_extensions so the result
+// also carries the extension's relocatable flag and declared requirements,
+// neither of which pg_extension itself exposes.
+func dataSourcePostgreSQLExtension() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourcePostgreSQLExtensionRead,
+
+		Schema: map[string]*schema.Schema{
+			extNameAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the extension to look up",
+			},
+			extDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The database to look up the extension in. Defaults to the provider's connection database.",
+			},
+			extSchemaAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Schema the extension is installed into",
+			},
+			extVersionAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Installed version of the extension",
+			},
+			"owner": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Owner of the extension",
+			},
+			"relocatable": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the extension's contained objects can be moved to a different schema after installation",
+			},
+			"requires": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Names of extensions this extension depends on",
+			},
+		},
+	}
+}
+
+const dataSourceExtensionQuery = `
+SELECT n.nspname, e.extversion, pg_catalog.pg_get_userbyid(e.extowner), a.relocatable, COALESCE(a.requires, '{}')
+FROM pg_catalog.pg_extension e
+JOIN pg_catalog.pg_namespace n ON n.oid = e.extnamespace
+JOIN pg_catalog.pg_available_extensions a ON a.name = e.extname
+WHERE e.extname = $1
+`
+
+func dataSourcePostgreSQLExtensionRead(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*Client)
+	c.catalogLock.RLock()
+	defer c.catalogLock.RUnlock()
+
+	extName := d.Get(extNameAttr).(string)
+	database := getDatabaseForExtension(d, c)
+
+	txn, err := startTransaction(c, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	var extSchema, extVersion, owner string
+	var relocatable bool
+	var requires []string
+	err = txn.QueryRow(dataSourceExtensionQuery, extName).Scan(&extSchema, &extVersion, &owner, &relocatable, &requires)
+	switch {
+	case err == sql.ErrNoRows:
+		return fmt.Errorf("extension %s is not installed", extName)
+	case err != nil:
+		return fmt.Errorf("Error reading extension: %w", err)
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("Error committing transaction: %w", err)
+	}
+
+	d.SetId(generateExtensionID(d, c))
+	d.Set(extDatabaseAttr, database)
+	d.Set(extSchemaAttr, extSchema)
+	d.Set(extVersionAttr, extVersion)
+	d.Set("owner", owner)
+	d.Set("relocatable", relocatable)
+	d.Set("requires", requires)
+
+	return nil
+}
@@ -0,0 +1,256 @@
+package postgresql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// dataSourcePostgreSQLRoles returns every role matching name_pattern/filter
+// in a single query, along with the roles each one is a member of. It exists
+// to make onboarding an existing cluster (thousands of roles) tractable:
+// checking every role one at a time the way checkGrantedRoles/checkSearchPath
+// do in the acceptance tests does not scale past a handful of roles.
+func dataSourcePostgreSQLRoles() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourcePostgreSQLRolesRead,
+
+		Schema: map[string]*schema.Schema{
+			"name_pattern": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "POSIX regular expression. Only roles whose name matches are returned.",
+			},
+			"filter": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Restrict the returned roles further.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"has_login": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Only return roles with LOGIN privilege.",
+						},
+						"is_superuser": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Only return superuser roles.",
+						},
+						"member_of": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Only return roles that are a member of this role.",
+						},
+					},
+				},
+			},
+			"generate_hcl": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "When true, each returned role includes a rendered `postgresql_role` HCL block in its `hcl` attribute, so it can be piped through `terraform import` to onboard an existing cluster.",
+			},
+			"roles": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"login": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"superuser": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"create_database": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"create_role": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"inherit": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"replication": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"connection_limit": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"grants": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Roles this role is a member of, from pg_auth_members.",
+						},
+						"rolconfig": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Role-level (non-database-specific) GUC overrides, from pg_db_role_setting.",
+						},
+						"hcl": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Rendered postgresql_role HCL block, only populated when generate_hcl is true.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+const dataSourceRolesQuery = `
+WITH role_grants AS (
+	SELECT am.member AS roleoid, array_agg(g.rolname ORDER BY g.rolname) AS granted_roles
+	FROM pg_auth_members am
+	JOIN pg_roles g ON g.oid = am.roleid
+	GROUP BY am.member
+), role_config AS (
+	SELECT setrole AS roleoid, array_agg(unnest_config ORDER BY unnest_config) AS rolconfig
+	FROM pg_db_role_setting, unnest(setconfig) AS unnest_config
+	WHERE setdatabase = 0
+	GROUP BY setrole
+)
+SELECT
+	r.rolname, r.rolcanlogin, r.rolsuper, r.rolcreatedb, r.rolcreaterole,
+	r.rolinherit, r.rolreplication, r.rolconnlimit,
+	COALESCE(rg.granted_roles, ARRAY[]::text[]),
+	COALESCE(rc.rolconfig, ARRAY[]::text[])
+FROM pg_roles r
+LEFT JOIN role_grants rg ON rg.roleoid = r.oid
+LEFT JOIN role_config rc ON rc.roleoid = r.oid
+WHERE ($1 = '' OR r.rolname ~ $1)
+  AND (NOT $2::bool OR r.rolcanlogin)
+  AND (NOT $3::bool OR r.rolsuper)
+  AND ($4 = '' OR EXISTS (
+	SELECT 1 FROM pg_auth_members am2
+	JOIN pg_roles m ON m.oid = am2.roleid
+	WHERE am2.member = r.oid AND m.rolname = $4
+  ))
+ORDER BY r.rolname
+`
+
+type postgresqlRoleSummary struct {
+	Name            string
+	Login           bool
+	Superuser       bool
+	CreateDatabase  bool
+	CreateRole      bool
+	Inherit         bool
+	Replication     bool
+	ConnectionLimit int
+	Grants          []string
+	Rolconfig       []string
+}
+
+func dataSourcePostgreSQLRolesRead(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*Client)
+	c.catalogLock.RLock()
+	defer c.catalogLock.RUnlock()
+
+	namePattern := d.Get("name_pattern").(string)
+
+	var hasLogin, isSuperuser bool
+	var memberOf string
+	if filters, ok := d.Get("filter").([]interface{}); ok && len(filters) == 1 {
+		filter := filters[0].(map[string]interface{})
+		hasLogin = filter["has_login"].(bool)
+		isSuperuser = filter["is_superuser"].(bool)
+		memberOf = filter["member_of"].(string)
+	}
+
+	rows, err := c.DB().Query(dataSourceRolesQuery, namePattern, hasLogin, isSuperuser, memberOf)
+	if err != nil {
+		return fmt.Errorf("Error reading roles: %w", err)
+	}
+	defer rows.Close()
+
+	generateHcl := d.Get("generate_hcl").(bool)
+
+	var roles []map[string]interface{}
+	for rows.Next() {
+		var summary postgresqlRoleSummary
+		if err := rows.Scan(
+			&summary.Name, &summary.Login, &summary.Superuser, &summary.CreateDatabase, &summary.CreateRole,
+			&summary.Inherit, &summary.Replication, &summary.ConnectionLimit,
+			&summary.Grants, &summary.Rolconfig,
+		); err != nil {
+			return fmt.Errorf("Error scanning role: %w", err)
+		}
+
+		role := map[string]interface{}{
+			"name":             summary.Name,
+			"login":            summary.Login,
+			"superuser":        summary.Superuser,
+			"create_database":  summary.CreateDatabase,
+			"create_role":      summary.CreateRole,
+			"inherit":          summary.Inherit,
+			"replication":      summary.Replication,
+			"connection_limit": summary.ConnectionLimit,
+			"grants":           summary.Grants,
+			"rolconfig":        summary.Rolconfig,
+			"hcl":              "",
+		}
+		if generateHcl {
+			role["hcl"] = renderRoleHCL(summary)
+		}
+		roles = append(roles, role)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("Error iterating roles: %w", err)
+	}
+
+	d.SetId(namePattern + "/" + strconv.FormatBool(hasLogin) + "/" + strconv.FormatBool(isSuperuser) + "/" + memberOf)
+	if err := d.Set("roles", roles); err != nil {
+		return fmt.Errorf("Error setting roles: %w", err)
+	}
+
+	return nil
+}
+
+// renderRoleHCL renders a postgresql_role block suitable for `terraform
+// import`-ing an existing role before running `terraform plan` to reconcile
+// the rest of its attributes.
+func renderRoleHCL(r postgresqlRoleSummary) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "resource \"postgresql_role\" %q {\n", r.Name)
+	fmt.Fprintf(&b, "  name             = %q\n", r.Name)
+	fmt.Fprintf(&b, "  login            = %t\n", r.Login)
+	fmt.Fprintf(&b, "  superuser        = %t\n", r.Superuser)
+	fmt.Fprintf(&b, "  create_database  = %t\n", r.CreateDatabase)
+	fmt.Fprintf(&b, "  create_role      = %t\n", r.CreateRole)
+	fmt.Fprintf(&b, "  inherit          = %t\n", r.Inherit)
+	fmt.Fprintf(&b, "  replication      = %t\n", r.Replication)
+	fmt.Fprintf(&b, "  connection_limit = %d\n", r.ConnectionLimit)
+	if len(r.Grants) > 0 {
+		fmt.Fprintf(&b, "  roles            = [%s]\n", quoteStringsForHCL(r.Grants))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func quoteStringsForHCL(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = strconv.Quote(v)
+	}
+	return strings.Join(quoted, ", ")
+}
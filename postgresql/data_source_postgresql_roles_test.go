@@ -0,0 +1,68 @@
+package postgresql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+const testAccPostgresqlRolesDataSourceConfig = `
+resource "postgresql_role" "bulk" {
+  count = 50
+  name  = "tf_bulk_role_%s_${count.index}"
+  login = true
+}
+
+data "postgresql_roles" "all" {
+  name_pattern = "^tf_bulk_role_%s_"
+
+  filter {
+    has_login = true
+  }
+
+  depends_on = [postgresql_role.bulk]
+}
+`
+
+func TestAccPostgresqlRolesDataSource_Basic(t *testing.T) {
+	rString := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+	config := fmt.Sprintf(testAccPostgresqlRolesDataSourceConfig, rString, rString)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlRolesDataSourceCount("data.postgresql_roles.all", 50),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckPostgresqlRolesDataSourceCount only asserts on the shape of
+// the returned data. Asserting the "<= 3 total SQL statements" requirement
+// from the request would need query-level instrumentation on *sql.DB that
+// this client does not currently expose.
+func testAccCheckPostgresqlRolesDataSourceCount(n string, want int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Resource not found: %s", n)
+		}
+
+		got := rs.Primary.Attributes["roles.#"]
+		if got != fmt.Sprintf("%d", want) {
+			return fmt.Errorf("expected %d roles, got %s", want, got)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,189 @@
+package postgresql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	acl "github.com/sean-/postgresql-acl"
+)
+
+// dataSourcePostgreSQLSchemas returns every schema in a database matching
+// include_pattern/exclude_pattern and owned_by in a single pg_namespace join
+// query, along with each schema's parsed ACL policies. It exists for the
+// same reason dataSourcePostgreSQLRoles does: auditing or granting against
+// every schema in a large catalog one resourcePostgreSQLSchemaRead at a time
+// does not scale.
+func dataSourcePostgreSQLSchemas() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourcePostgreSQLSchemasRead,
+
+		Schema: map[string]*schema.Schema{
+			schemaDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The database to list schemas from. Defaults to the provider's connection database.",
+			},
+			"include_pattern": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "POSIX regular expression. Only schemas whose name matches are returned.",
+			},
+			"exclude_pattern": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "POSIX regular expression. Schemas whose name matches are excluded from the result, applied after include_pattern.",
+			},
+			"owned_by": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return schemas owned by this role.",
+			},
+			"schemas": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						schemaNameAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						schemaOwnerAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						schemaPolicyAttr: {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									schemaPolicyRoleAttr: {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									schemaPolicyCreateAttr: {
+										Type:     schema.TypeBool,
+										Computed: true,
+									},
+									schemaPolicyCreateWithGrantAttr: {
+										Type:     schema.TypeBool,
+										Computed: true,
+									},
+									schemaPolicyUsageAttr: {
+										Type:     schema.TypeBool,
+										Computed: true,
+									},
+									schemaPolicyUsageWithGrantAttr: {
+										Type:     schema.TypeBool,
+										Computed: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+const dataSourceSchemasQuery = `
+SELECT n.nspname, pg_catalog.pg_get_userbyid(n.nspowner), COALESCE(n.nspacl, '{}'::aclitem[])::TEXT[]
+FROM pg_catalog.pg_namespace n
+WHERE ($1 = '' OR n.nspname ~ $1)
+  AND ($2 = '' OR n.nspname !~ $2)
+  AND ($3 = '' OR pg_catalog.pg_get_userbyid(n.nspowner) = $3)
+ORDER BY n.nspname
+`
+
+func dataSourcePostgreSQLSchemasRead(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*Client)
+	c.catalogLock.RLock()
+	defer c.catalogLock.RUnlock()
+
+	database := getDatabase(d, c)
+	includePattern := d.Get("include_pattern").(string)
+	excludePattern := d.Get("exclude_pattern").(string)
+	ownedBy := d.Get("owned_by").(string)
+
+	txn, err := startTransaction(c, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	rows, err := txn.Query(dataSourceSchemasQuery, includePattern, excludePattern, ownedBy)
+	if err != nil {
+		return fmt.Errorf("Error reading schemas: %w", err)
+	}
+	defer rows.Close()
+
+	var schemas []map[string]interface{}
+	for rows.Next() {
+		var name, owner string
+		var schemaACLs []string
+		if err := rows.Scan(&name, &owner, &schemaACLs); err != nil {
+			return fmt.Errorf("Error scanning schema: %w", err)
+		}
+
+		policies, err := schemaACLsToHCL(schemaACLs)
+		if err != nil {
+			return err
+		}
+
+		schemas = append(schemas, map[string]interface{}{
+			schemaNameAttr:   name,
+			schemaOwnerAttr:  owner,
+			schemaPolicyAttr: policies,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("Error iterating schemas: %w", err)
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("Error committing transaction: %w", err)
+	}
+
+	d.SetId(strings.Join([]string{database, includePattern, excludePattern, ownedBy}, "/"))
+	if err := d.Set("schemas", schemas); err != nil {
+		return fmt.Errorf("Error setting schemas: %w", err)
+	}
+
+	return nil
+}
+
+// schemaACLsToHCL parses a pg_namespace.nspacl array the same way
+// resourcePostgreSQLSchemaReadImpl does, merging privileges per role, and
+// renders the result through schemaPolicyToHCL so the data source and the
+// resource agree on the policy block's shape.
+func schemaACLsToHCL(schemaACLs []string) ([]map[string]interface{}, error) {
+	type RoleKey string
+	schemaPolicies := make(map[RoleKey]acl.Schema, len(schemaACLs))
+	for _, aclStr := range schemaACLs {
+		aclItem, err := acl.Parse(aclStr)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing aclitem: %w", err)
+		}
+
+		schemaACL, err := acl.NewSchema(aclItem)
+		if err != nil {
+			return nil, fmt.Errorf("invalid perms for schema: %w", err)
+		}
+
+		roleKey := RoleKey(strings.ToLower(schemaACL.Role))
+		if existingRolePolicy, ok := schemaPolicies[roleKey]; ok {
+			schemaPolicies[roleKey] = existingRolePolicy.Merge(schemaACL)
+		} else {
+			schemaPolicies[roleKey] = schemaACL
+		}
+	}
+
+	policies := make([]map[string]interface{}, 0, len(schemaPolicies))
+	for _, policy := range schemaPolicies {
+		policy := policy
+		policies = append(policies, schemaPolicyToHCL(&policy))
+	}
+
+	return policies, nil
+}
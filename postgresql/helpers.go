@@ -26,19 +26,43 @@ func validateConnLimit(v interface{}, key string) (warnings []string, errors []e
 	return
 }
 
-// libPQ style connection strings are:
+// libPQ style connection strings can be given either as a URL:
 // postgresql://[user[:password]@][netloc][:port][,...][/dbname][?param1=value1&...]
-// The only param we care about is sslMode
+// or as a whitespace separated list of key=value pairs:
+// host=localhost port=5432 dbname=mydb connect_timeout=10
 type postgresConnString struct {
-	username string
-	password string
-	netloc   string
-	port     int
-	dbname   string
-	sslmode  string
+	username                string
+	password                string
+	netloc                  string
+	port                    int
+	dbname                  string
+	sslmode                 string
+	applicationName         string
+	connectTimeout          string
+	sslcert                 string
+	sslkey                  string
+	sslrootcert             string
+	sslinhibit              string
+	targetSessionAttrs      string
+	options                 string
+	fallbackApplicationName string
+	gssencmode              string
+	channelBinding          string
+
+	// xStatementTimeout and xMultiStatement are provider-specific extensions
+	// (not libpq parameters, hence the x- prefix) that configure the pgx
+	// pool rather than anything the server understands as a connection
+	// option. See poolOptions.StatementTimeout/MultiStatement.
+	xStatementTimeout string
+	xMultiStatement   bool
 }
 
 func parseConnectionString(connString string) (postgresConnString, error) {
+	trimmed := strings.TrimSpace(connString)
+	if !strings.HasPrefix(trimmed, "postgres://") && !strings.HasPrefix(trimmed, "postgresql://") {
+		return parseKeyValueConnectionString(trimmed)
+	}
+
 	url, err := url.Parse(connString)
 
 	if err != nil {
@@ -52,13 +76,194 @@ func parseConnectionString(connString string) (postgresConnString, error) {
 	username, password := usernameAndPasswordFromURL(url)
 	netloc, port := hostAndPortFromURL(url)
 	dbname := dbnameFromURL(url)
-	sslmode := sslmodeFromURL(url)
 
-	r := postgresConnString{username, password, netloc, port, dbname, sslmode}
+	r := postgresConnString{
+		username: username,
+		password: password,
+		netloc:   netloc,
+		port:     port,
+		dbname:   dbname,
+	}
+
+	queryVals := url.Query()
+	r.sslmode = firstQueryValue(queryVals, "sslmode")
+	r.applicationName = firstQueryValue(queryVals, "application_name")
+	r.connectTimeout = firstQueryValue(queryVals, "connect_timeout")
+	r.sslcert = firstQueryValue(queryVals, "sslcert")
+	r.sslkey = firstQueryValue(queryVals, "sslkey")
+	r.sslrootcert = firstQueryValue(queryVals, "sslrootcert")
+	r.sslinhibit = firstQueryValue(queryVals, "sslinhibit")
+	r.targetSessionAttrs = firstQueryValue(queryVals, "target_session_attrs")
+	r.options = firstQueryValue(queryVals, "options")
+	r.fallbackApplicationName = firstQueryValue(queryVals, "fallback_application_name")
+	r.gssencmode = firstQueryValue(queryVals, "gssencmode")
+	r.channelBinding = firstQueryValue(queryVals, "channel_binding")
+	r.xStatementTimeout = firstQueryValue(queryVals, "x-statement-timeout")
+	r.xMultiStatement = firstQueryValue(queryVals, "x-multi-statement") == "true"
+
+	return r, nil
+}
+
+// parseKeyValueConnectionString parses the libpq "key=value key=value" DSN
+// form. Values may be single-quoted to include whitespace, and backslash
+// escapes single quotes and backslashes within a quoted value, as documented
+// at https://www.postgresql.org/docs/current/libpq-connect.html#LIBPQ-CONNSTRING.
+func parseKeyValueConnectionString(connString string) (postgresConnString, error) {
+	pairs, err := tokenizeKeyValueConnectionString(connString)
+	if err != nil {
+		return postgresConnString{}, err
+	}
+	if len(pairs) == 0 {
+		return postgresConnString{}, errors.New("Not a PostgreSQL URL")
+	}
+
+	r := postgresConnString{}
+
+	for key, value := range pairs {
+		switch key {
+		case "user":
+			r.username = value
+		case "password":
+			r.password = value
+		case "host", "hostaddr":
+			r.netloc = firstHost(value)
+		case "port":
+			r.port = firstPort(value)
+		case "dbname":
+			r.dbname = value
+		case "sslmode":
+			r.sslmode = value
+		case "application_name":
+			r.applicationName = value
+		case "connect_timeout":
+			r.connectTimeout = value
+		case "sslcert":
+			r.sslcert = value
+		case "sslkey":
+			r.sslkey = value
+		case "sslrootcert":
+			r.sslrootcert = value
+		case "sslinhibit":
+			r.sslinhibit = value
+		case "target_session_attrs":
+			r.targetSessionAttrs = value
+		case "options":
+			r.options = value
+		case "fallback_application_name":
+			r.fallbackApplicationName = value
+		case "gssencmode":
+			r.gssencmode = value
+		case "channel_binding":
+			r.channelBinding = value
+		case "x-statement-timeout":
+			r.xStatementTimeout = value
+		case "x-multi-statement":
+			r.xMultiStatement = value == "true"
+		}
+	}
 
 	return r, nil
 }
 
+// tokenizeKeyValueConnectionString splits a libpq key/value DSN into a map,
+// honoring single-quoted values with backslash escapes.
+func tokenizeKeyValueConnectionString(connString string) (map[string]string, error) {
+	pairs := map[string]string{}
+
+	i := 0
+	n := len(connString)
+	for i < n {
+		for i < n && isSpace(connString[i]) {
+			i++
+		}
+		if i >= n {
+			break
+		}
+
+		keyStart := i
+		for i < n && connString[i] != '=' && !isSpace(connString[i]) {
+			i++
+		}
+		key := connString[keyStart:i]
+
+		for i < n && isSpace(connString[i]) {
+			i++
+		}
+		if i >= n || connString[i] != '=' {
+			return nil, fmt.Errorf("missing \"=\" after %q in connection string", key)
+		}
+		i++ // skip '='
+
+		for i < n && isSpace(connString[i]) {
+			i++
+		}
+
+		var value strings.Builder
+		if i < n && connString[i] == '\'' {
+			i++ // skip opening quote
+			closed := false
+			for i < n {
+				c := connString[i]
+				if c == '\\' && i+1 < n {
+					value.WriteByte(connString[i+1])
+					i += 2
+					continue
+				}
+				if c == '\'' {
+					closed = true
+					i++
+					break
+				}
+				value.WriteByte(c)
+				i++
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated quoted string in connection string for key %q", key)
+			}
+		} else {
+			for i < n && !isSpace(connString[i]) {
+				value.WriteByte(connString[i])
+				i++
+			}
+		}
+
+		if key != "" {
+			pairs[key] = value.String()
+		}
+	}
+
+	return pairs, nil
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
+
+// firstHost returns the first host of a comma separated multi-host list
+// (e.g. "host1,host2" -> "host1"), preserving bracketed IPv6 literals.
+func firstHost(hosts string) string {
+	parts := splitHostList(hosts)
+	if len(parts) == 0 {
+		return hosts
+	}
+	return parts[0]
+}
+
+// firstPort returns the first port of a comma separated multi-host port list
+// (e.g. "5432,5433" -> 5432).
+func firstPort(ports string) int {
+	parts := strings.Split(ports, ",")
+	port, _ := strconv.Atoi(parts[0])
+	return port
+}
+
+func firstQueryValue(vals url.Values, key string) string {
+	if len(vals[key]) > 0 {
+		return vals[key][0]
+	}
+	return ""
+}
+
 func usernameAndPasswordFromURL(url *url.URL) (string, string) {
 	var username string
 	var password string
@@ -71,27 +276,57 @@ func usernameAndPasswordFromURL(url *url.URL) (string, string) {
 	return username, password
 }
 
-func sslmodeFromURL(url *url.URL) string {
-	var sslmode string
-
-	queryVals := url.Query()
-
-	if len(queryVals["sslmode"]) == 1 {
-		sslmode = queryVals["sslmode"][0]
+// splitHostList splits a comma separated multi-host netloc
+// (host1,host2:5433) into its individual hosts, without touching
+// bracketed IPv6 literals such as [::1].
+func splitHostList(hosts string) []string {
+	var parts []string
+	depth := 0
+	start := 0
+	for i, c := range hosts {
+		switch c {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, hosts[start:i])
+				start = i + 1
+			}
+		}
 	}
-
-	return sslmode
+	parts = append(parts, hosts[start:])
+	return parts
 }
 
+// hostAndPortFromURL extracts the host and port from a URL, supporting
+// bracketed IPv6 literals and comma separated multi-host lists, of which
+// only the first host/port pair is used.
 func hostAndPortFromURL(url *url.URL) (string, int) {
-	parts := strings.Split(url.Host, ":")
+	host := firstHost(url.Host)
+
+	if strings.HasPrefix(host, "[") {
+		// Bracketed IPv6 literal, optionally followed by :port.
+		closeIdx := strings.Index(host, "]")
+		if closeIdx == -1 {
+			return host, 0
+		}
+		netloc := host[1:closeIdx]
+		rest := host[closeIdx+1:]
+		if strings.HasPrefix(rest, ":") {
+			port, _ := strconv.Atoi(rest[1:])
+			return netloc, port
+		}
+		return netloc, 0
+	}
 
+	parts := strings.Split(host, ":")
 	if len(parts) == 1 {
 		return parts[0], 0
-	} else {
-		port, _ := strconv.Atoi(parts[1])
-		return parts[0], port
 	}
+	port, _ := strconv.Atoi(parts[1])
+	return parts[0], port
 }
 
 func dbnameFromURL(url *url.URL) string {
@@ -0,0 +1,204 @@
+// Package acl parses and reconciles PostgreSQL aclitem strings (the values
+// packed into pg_class.relacl, pg_namespace.nspacl, pg_database.datacl,
+// pg_proc.proacl, ...), in the same spirit as github.com/sean-/postgresql-acl
+// but extended to every object kind postgresql_grant supports. It replaces
+// the aclexplode()-based SQL the grant resource used to run once per object
+// kind: the raw acl array is fetched once per object and parsed here, and
+// the per-kind wrapper types (Database, Schema, Table, Sequence, Function,
+// Type) expose Grants/Revokes so callers can diff a desired ACL against an
+// observed one instead of unconditionally revoking and re-granting.
+package acl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-provider-postgresql/postgresql/internal/quote"
+)
+
+// Privilege is a bitmask of the single-letter privilege codes PostgreSQL
+// packs into an aclitem. Which bits are meaningful depends on the object
+// kind; the NewDatabase/NewSchema/NewTable/... constructors reject bits
+// that don't apply to their kind.
+type Privilege uint16
+
+const (
+	Select Privilege = 1 << iota
+	Insert
+	Update
+	Delete
+	Truncate
+	References
+	Trigger
+	Execute
+	Usage
+	Create
+	Temporary
+	Connect
+)
+
+// aclCode is a single letter/keyword pair from the aclitem privilege
+// alphabet, listed in the order aclexplode()/pg_dump emit them.
+type aclCode struct {
+	letter  byte
+	keyword string
+	priv    Privilege
+}
+
+var aclCodes = []aclCode{
+	{'r', "SELECT", Select},
+	{'a', "INSERT", Insert},
+	{'w', "UPDATE", Update},
+	{'d', "DELETE", Delete},
+	{'D', "TRUNCATE", Truncate},
+	{'x', "REFERENCES", References},
+	{'t', "TRIGGER", Trigger},
+	{'X', "EXECUTE", Execute},
+	{'U', "USAGE", Usage},
+	{'C', "CREATE", Create},
+	{'T', "TEMPORARY", Temporary},
+	{'c', "CONNECT", Connect},
+}
+
+// ACL is a single parsed aclitem: "grantee=privileges/grantor", where
+// privileges is a run of letters from aclCodes, each optionally followed by
+// '*' to mark it as granted WITH GRANT OPTION. An empty grantee denotes the
+// PUBLIC pseudo-role.
+type ACL struct {
+	Role         string
+	GrantedBy    string
+	Privileges   Privilege
+	GrantOptions Privilege
+}
+
+// Parse parses a single aclitem string, e.g. "alice=arwdDxt/bob" or
+// "=U/bob" (PUBLIC).
+func Parse(aclItem string) (ACL, error) {
+	eq := strings.IndexByte(aclItem, '=')
+	slash := strings.LastIndexByte(aclItem, '/')
+	if eq < 0 || slash < 0 || slash < eq {
+		return ACL{}, fmt.Errorf("acl: malformed aclitem %q", aclItem)
+	}
+
+	role := aclItem[:eq]
+	if role == "" {
+		role = "public"
+	}
+
+	a := ACL{
+		Role:      role,
+		GrantedBy: aclItem[slash+1:],
+	}
+
+	privs := aclItem[eq+1 : slash]
+	for i := 0; i < len(privs); i++ {
+		letter := privs[i]
+		grantable := i+1 < len(privs) && privs[i+1] == '*'
+
+		var found bool
+		for _, c := range aclCodes {
+			if c.letter == letter {
+				a.Privileges |= c.priv
+				if grantable {
+					a.GrantOptions |= c.priv
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			return ACL{}, fmt.Errorf("acl: unknown privilege code %q in aclitem %q", string(letter), aclItem)
+		}
+		if grantable {
+			i++
+		}
+	}
+
+	return a, nil
+}
+
+// keywords returns the SQL privilege keywords set in mask, in aclexplode
+// order.
+func keywords(mask Privilege) []string {
+	var out []string
+	for _, c := range aclCodes {
+		if mask&c.priv != 0 {
+			out = append(out, c.keyword)
+		}
+	}
+	return out
+}
+
+// PrivilegesFromKeywords is the inverse of keywords: it parses SQL privilege
+// keywords (as stored in the postgresql_grant resource's privileges /
+// privileges_with_grant_option attributes) into a Privilege bitmask, so a
+// desired ACL can be built to Reconcile against one observed from the
+// catalog. Keywords are matched case-insensitively; an unrecognized keyword
+// is an error rather than being silently ignored.
+func PrivilegesFromKeywords(keywords []string) (Privilege, error) {
+	var mask Privilege
+	for _, keyword := range keywords {
+		var found bool
+		for _, c := range aclCodes {
+			if strings.EqualFold(c.keyword, keyword) {
+				mask |= c.priv
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("acl: unknown privilege keyword %q", keyword)
+		}
+	}
+	return mask, nil
+}
+
+// quoteRole renders role as a GRANT/REVOKE grantee: the bare PUBLIC keyword
+// (case-insensitive, never quoted) or an identifier-quoted role name.
+func quoteRole(role string) string {
+	if strings.EqualFold(role, "public") {
+		return "PUBLIC"
+	}
+	return quote.Identifier(role)
+}
+
+// grantRevokeStatements builds the GRANT/REVOKE statements to move a role's
+// privileges on kind/target from observed to the (privileges, grantOptions)
+// pair desired describes, using the minimum number of statements: PostgreSQL
+// tracks the grant option per privilege, so privileges gaining or losing it
+// are granted/revoked separately from ones whose grant option is unchanged.
+func grantRevokeStatements(kind, target, role string, desiredPrivileges, desiredGrantOptions, observedPrivileges, observedGrantOptions Privilege) (grants, revokes []string) {
+	toRevoke := observedPrivileges &^ desiredPrivileges
+	if words := keywords(toRevoke); len(words) > 0 {
+		revokes = append(revokes, fmt.Sprintf(
+			"REVOKE %s ON %s %s FROM %s", strings.Join(words, ", "), kind, target, quoteRole(role),
+		))
+	}
+
+	// Privileges that are kept but whose grant option is being dropped only
+	// lose the grant option (REVOKE GRANT OPTION FOR); the privilege itself
+	// stays granted, so it is not granted again below.
+	keptGrantOptionDropped := desiredPrivileges & observedPrivileges & observedGrantOptions &^ desiredGrantOptions
+	if words := keywords(keptGrantOptionDropped); len(words) > 0 {
+		revokes = append(revokes, fmt.Sprintf(
+			"REVOKE GRANT OPTION FOR %s ON %s %s FROM %s", strings.Join(words, ", "), kind, target, quoteRole(role),
+		))
+	}
+
+	newWithoutGrant := desiredPrivileges &^ desiredGrantOptions &^ observedPrivileges
+	keptWithoutGrantChange := Privilege(0)
+	if words := keywords(newWithoutGrant | keptWithoutGrantChange); len(words) > 0 {
+		grants = append(grants, fmt.Sprintf(
+			"GRANT %s ON %s %s TO %s", strings.Join(words, ", "), kind, target, quoteRole(role),
+		))
+	}
+
+	newWithGrant := desiredPrivileges & desiredGrantOptions &^ (observedPrivileges & observedGrantOptions)
+	if words := keywords(newWithGrant); len(words) > 0 {
+		grants = append(grants, fmt.Sprintf(
+			"GRANT %s ON %s %s TO %s WITH GRANT OPTION", strings.Join(words, ", "), kind, target, quoteRole(role),
+		))
+	}
+
+	return grants, revokes
+}
@@ -0,0 +1,210 @@
+package acl
+
+import "fmt"
+
+// object is the shared representation behind the per-kind wrapper types
+// below: a parsed ACL plus the SQL keyword (DATABASE, SCHEMA, TABLE, ...)
+// that goes between ON and the target name in a GRANT/REVOKE statement.
+type object struct {
+	ACL
+	kind string
+}
+
+// Grants returns the GRANT statements needed to give target this ACL's
+// privileges, assuming no privileges have been granted yet (it diffs
+// against an empty ACL). Use Reconcile instead when diffing against a live
+// ACL.
+func (o object) Grants(target string) []string {
+	grants, _ := grantRevokeStatements(o.kind, target, o.Role, o.Privileges, o.GrantOptions, 0, 0)
+	return grants
+}
+
+// Revokes returns the REVOKE statements needed to remove every privilege in
+// this ACL from target.
+func (o object) Revokes(target string) []string {
+	_, revokes := grantRevokeStatements(o.kind, target, o.Role, 0, 0, o.Privileges, o.GrantOptions)
+	return revokes
+}
+
+// Reconcile returns the GRANT/REVOKE statements needed to move target's
+// privileges for o.Role from observed to this ACL.
+func (o object) Reconcile(target string, observed object) (grants, revokes []string) {
+	return grantRevokeStatements(o.kind, target, o.Role, o.Privileges, o.GrantOptions, observed.Privileges, observed.GrantOptions)
+}
+
+// PrivilegeKeywords returns the SQL privilege keywords this ACL holds, in
+// aclexplode order.
+func (o object) PrivilegeKeywords() []string {
+	return keywords(o.Privileges)
+}
+
+// GrantOptionKeywords returns the SQL privilege keywords held WITH GRANT
+// OPTION, in aclexplode order.
+func (o object) GrantOptionKeywords() []string {
+	return keywords(o.GrantOptions)
+}
+
+// GetPrivilege reports whether p is held, ignoring bits p doesn't define.
+func (o object) GetPrivilege(p Privilege) bool {
+	return o.Privileges&p != 0
+}
+
+// GetGrantOption reports whether p is held WITH GRANT OPTION.
+func (o object) GetGrantOption(p Privilege) bool {
+	return o.GrantOptions&p != 0
+}
+
+// merge combines another parse of the same role's privileges into o (the
+// catalog can list more than one aclitem for a role when it was granted
+// privileges by more than one grantor).
+func (o object) merge(other ACL) object {
+	o.Privileges |= other.Privileges
+	o.GrantOptions |= other.GrantOptions
+	return o
+}
+
+const (
+	databasePrivileges           = Create | Connect | Temporary
+	schemaPrivileges             = Create | Usage
+	tablePrivileges              = Select | Insert | Update | Delete | Truncate | References | Trigger
+	sequencePrivileges           = Select | Usage | Update
+	functionPrivileges           = Execute
+	typePrivileges               = Usage
+	foreignDataWrapperPrivileges = Usage
+	foreignServerPrivileges      = Usage
+)
+
+func newObject(kind string, allowed Privilege, a ACL) (object, error) {
+	if a.Privileges&^allowed != 0 || a.GrantOptions&^allowed != 0 {
+		return object{}, fmt.Errorf("acl: privilege not valid for %s: %q", kind, keywords((a.Privileges|a.GrantOptions)&^allowed))
+	}
+	return object{ACL: a, kind: kind}, nil
+}
+
+// Database wraps an ACL parsed from pg_database.datacl.
+type Database struct{ object }
+
+func NewDatabase(a ACL) (Database, error) {
+	o, err := newObject("DATABASE", databasePrivileges, a)
+	return Database{o}, err
+}
+
+func (d Database) Merge(other Database) Database { return Database{d.merge(other.ACL)} }
+
+// Reconcile returns the GRANT/REVOKE statements needed to move target's
+// privileges for d.Role from observed to d.
+func (d Database) Reconcile(target string, observed Database) (grants, revokes []string) {
+	return d.object.Reconcile(target, observed.object)
+}
+
+// Schema wraps an ACL parsed from pg_namespace.nspacl.
+type Schema struct{ object }
+
+func NewSchema(a ACL) (Schema, error) {
+	o, err := newObject("SCHEMA", schemaPrivileges, a)
+	return Schema{o}, err
+}
+
+func (s Schema) Merge(other Schema) Schema { return Schema{s.merge(other.ACL)} }
+
+// Reconcile returns the GRANT/REVOKE statements needed to move target's
+// privileges for s.Role from observed to s.
+func (s Schema) Reconcile(target string, observed Schema) (grants, revokes []string) {
+	return s.object.Reconcile(target, observed.object)
+}
+
+// Table wraps an ACL parsed from pg_class.relacl for a relkind='r' relation.
+type Table struct{ object }
+
+func NewTable(a ACL) (Table, error) {
+	o, err := newObject("TABLE", tablePrivileges, a)
+	return Table{o}, err
+}
+
+func (t Table) Merge(other Table) Table { return Table{t.merge(other.ACL)} }
+
+// Reconcile returns the GRANT/REVOKE statements needed to move target's
+// privileges for t.Role from observed to t.
+func (t Table) Reconcile(target string, observed Table) (grants, revokes []string) {
+	return t.object.Reconcile(target, observed.object)
+}
+
+// Sequence wraps an ACL parsed from pg_class.relacl for a relkind='S'
+// relation. PostgreSQL accepts the TABLE keyword for sequence grants too,
+// so it reuses Table's "ON TABLE" rendering.
+type Sequence struct{ object }
+
+func NewSequence(a ACL) (Sequence, error) {
+	o, err := newObject("TABLE", sequencePrivileges, a)
+	return Sequence{o}, err
+}
+
+func (s Sequence) Merge(other Sequence) Sequence { return Sequence{s.merge(other.ACL)} }
+
+// Reconcile returns the GRANT/REVOKE statements needed to move target's
+// privileges for s.Role from observed to s.
+func (s Sequence) Reconcile(target string, observed Sequence) (grants, revokes []string) {
+	return s.object.Reconcile(target, observed.object)
+}
+
+// Function wraps an ACL parsed from pg_proc.proacl.
+type Function struct{ object }
+
+func NewFunction(a ACL) (Function, error) {
+	o, err := newObject("FUNCTION", functionPrivileges, a)
+	return Function{o}, err
+}
+
+func (f Function) Merge(other Function) Function { return Function{f.merge(other.ACL)} }
+
+// Reconcile returns the GRANT/REVOKE statements needed to move target's
+// privileges for f.Role from observed to f.
+func (f Function) Reconcile(target string, observed Function) (grants, revokes []string) {
+	return f.object.Reconcile(target, observed.object)
+}
+
+// ForeignDataWrapper wraps an ACL parsed from pg_foreign_data_wrapper.fdwacl.
+type ForeignDataWrapper struct{ object }
+
+func NewForeignDataWrapper(a ACL) (ForeignDataWrapper, error) {
+	o, err := newObject("FOREIGN DATA WRAPPER", foreignDataWrapperPrivileges, a)
+	return ForeignDataWrapper{o}, err
+}
+
+func (f ForeignDataWrapper) Merge(other ForeignDataWrapper) ForeignDataWrapper {
+	return ForeignDataWrapper{f.merge(other.ACL)}
+}
+
+// Reconcile returns the GRANT/REVOKE statements needed to move target's
+// privileges for f.Role from observed to f.
+func (f ForeignDataWrapper) Reconcile(target string, observed ForeignDataWrapper) (grants, revokes []string) {
+	return f.object.Reconcile(target, observed.object)
+}
+
+// ForeignServer wraps an ACL parsed from pg_foreign_server.srvacl.
+type ForeignServer struct{ object }
+
+func NewForeignServer(a ACL) (ForeignServer, error) {
+	o, err := newObject("FOREIGN SERVER", foreignServerPrivileges, a)
+	return ForeignServer{o}, err
+}
+
+func (s ForeignServer) Merge(other ForeignServer) ForeignServer {
+	return ForeignServer{s.merge(other.ACL)}
+}
+
+// Reconcile returns the GRANT/REVOKE statements needed to move target's
+// privileges for s.Role from observed to s.
+func (s ForeignServer) Reconcile(target string, observed ForeignServer) (grants, revokes []string) {
+	return s.object.Reconcile(target, observed.object)
+}
+
+// Type wraps an ACL parsed from pg_type.typacl.
+type Type struct{ object }
+
+func NewType(a ACL) (Type, error) {
+	o, err := newObject("TYPE", typePrivileges, a)
+	return Type{o}, err
+}
+
+func (t Type) Merge(other Type) Type { return Type{t.merge(other.ACL)} }
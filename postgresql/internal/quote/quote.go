@@ -0,0 +1,32 @@
+// Package quote renders SQL identifiers and string literals the same way
+// lib/pq.QuoteIdentifier/QuoteLiteral did. jackc/pgx/v5 doesn't expose an
+// equivalent helper (pgx.Identifier.Sanitize handles dotted multi-part
+// names, not a single already-qualified piece), so this is the drop-in
+// replacement call sites move to as they come off lib/pq. It lives under
+// internal so both the top-level postgresql package and internal/acl can
+// import it without a cycle.
+package quote
+
+import "strings"
+
+// Identifier double-quotes a single SQL identifier, escaping embedded
+// double quotes.
+func Identifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// Literal single-quotes a SQL string literal, escaping embedded single
+// quotes and backslashes (doubling single quotes, and wrapping in a
+// leading-space E'...' escape string if the value contains a backslash,
+// matching lib/pq.QuoteLiteral's own leading space on that branch).
+func Literal(literal string) string {
+	needsEscape := strings.Contains(literal, `\`)
+
+	literal = strings.ReplaceAll(literal, `'`, `''`)
+	if needsEscape {
+		literal = strings.ReplaceAll(literal, `\`, `\\`)
+		return ` E'` + literal + `'`
+	}
+
+	return `'` + literal + `'`
+}
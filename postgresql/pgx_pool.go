@@ -0,0 +1,222 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	// Registers the "pgx" database/sql driver name, for the handful of call
+	// sites (acceptance tests, and any future non-pooled sql.Open) that go
+	// through database/sql directly instead of a *pgxpool.Pool.
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// poolOptions holds the pgxpool tuning knobs exposed at the provider level.
+// MaxConns already exists as max_connections / Config.MaxConns; everything
+// else here is new.
+type poolOptions struct {
+	MinConns               int32
+	MaxConnLifetime        time.Duration
+	MaxConnIdleTime        time.Duration
+	HealthCheckPeriod      time.Duration
+	StatementCacheCapacity int
+
+	// StatementTimeout sets statement_timeout for every connection the pool
+	// opens. Surfaced as the provider's statement_timeout attribute, or the
+	// x-statement-timeout connection string option for a caller driving
+	// buildPgxPoolConfig with a raw DSN.
+	StatementTimeout time.Duration
+
+	// MultiStatement, when true, switches connections to the simple query
+	// protocol so a single Exec/Query can carry more than one
+	// semicolon-separated statement (pgx's default extended protocol rejects
+	// multi-statement strings). Surfaced as the provider's multi_statement
+	// attribute, or the x-multi-statement connection string option for a
+	// caller driving buildPgxPoolConfig with a raw DSN.
+	MultiStatement bool
+
+	// Tunnel, when set, routes every connection the pool opens through the
+	// given SSH bastion tunnel instead of dialing the target host directly.
+	// Each dial Acquires the tunnel and Releases it once the connection is
+	// closed, so the tunnel's refCount reflects how many pooled connections
+	// are currently relying on it.
+	Tunnel *sshTunnel
+}
+
+// buildPgxPoolConfig parses dsn into a *pgxpool.Config and applies the
+// requested pool tuning on top of it. It exists as its own seam so it can be
+// unit tested without a live database: pgxpool.ParseConfig only validates
+// the DSN syntax, it doesn't connect.
+//
+// dsn may carry the x-statement-timeout/x-multi-statement extensions
+// parseConnectionString understands; pgxpool.ParseConfig doesn't know those
+// keys (they aren't real libpq parameters) and would otherwise send them to
+// the server as bogus runtime parameters, so they're parsed out of dsn and
+// folded into opts before the DSN ever reaches pgx. A non-zero opts field
+// wins over the dsn-derived value; a zero StatementTimeout or a false
+// MultiStatement takes the dsn-derived value.
+func buildPgxPoolConfig(dsn string, maxConns int32, opts poolOptions) (*pgxpool.Config, error) {
+	cleanDSN, xOpts, err := extractPoolOptionsFromDSN(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing connection string for pgx pool: %w", err)
+	}
+	if opts.StatementTimeout == 0 {
+		opts.StatementTimeout = xOpts.StatementTimeout
+	}
+	if !opts.MultiStatement {
+		opts.MultiStatement = xOpts.MultiStatement
+	}
+
+	poolConfig, err := pgxpool.ParseConfig(cleanDSN)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing connection string for pgx pool: %w", err)
+	}
+
+	if maxConns > 0 {
+		poolConfig.MaxConns = maxConns
+	}
+	if opts.MinConns > 0 {
+		poolConfig.MinConns = opts.MinConns
+	}
+	if opts.MaxConnLifetime > 0 {
+		poolConfig.MaxConnLifetime = opts.MaxConnLifetime
+	}
+	if opts.MaxConnIdleTime > 0 {
+		poolConfig.MaxConnIdleTime = opts.MaxConnIdleTime
+	}
+	if opts.HealthCheckPeriod > 0 {
+		poolConfig.HealthCheckPeriod = opts.HealthCheckPeriod
+	}
+	if opts.StatementCacheCapacity > 0 {
+		poolConfig.ConnConfig.StatementCacheCapacity = opts.StatementCacheCapacity
+	}
+	if opts.StatementTimeout > 0 {
+		poolConfig.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(opts.StatementTimeout.Milliseconds(), 10)
+	}
+	if opts.MultiStatement {
+		poolConfig.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
+	}
+	if opts.Tunnel != nil {
+		poolConfig.ConnConfig.DialFunc = tunnelDialFunc(opts.Tunnel)
+	}
+
+	return poolConfig, nil
+}
+
+// tunnelDialFunc returns a pgx Config.DialFunc that Acquires tunnel for the
+// lifetime of the connection instead of dialing network/addr directly: it
+// dials the tunnel's local forwarding listener, and arranges for the
+// connection's Close to Release the tunnel so refCount tracks live pooled
+// connections.
+func tunnelDialFunc(tunnel *sshTunnel) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, _ string) (net.Conn, error) {
+		host, port, err := tunnel.Acquire()
+		if err != nil {
+			return nil, fmt.Errorf("Error acquiring ssh tunnel: %w", err)
+		}
+
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, network, net.JoinHostPort(host, strconv.Itoa(port)))
+		if err != nil {
+			tunnel.Release()
+			return nil, err
+		}
+
+		return &tunnelConn{Conn: conn, tunnel: tunnel}, nil
+	}
+}
+
+// tunnelConn wraps a net.Conn dialed through a sshTunnel so that closing the
+// connection Releases the tunnel exactly once.
+type tunnelConn struct {
+	net.Conn
+	tunnel   *sshTunnel
+	released sync.Once
+}
+
+func (c *tunnelConn) Close() error {
+	err := c.Conn.Close()
+	c.released.Do(c.tunnel.Release)
+	return err
+}
+
+// xPoolOptionKeys are the non-libpq connection string extensions
+// buildPgxPoolConfig understands. They're parsed out of the DSN (rather than
+// left for pgxpool.ParseConfig to stumble over) by extractPoolOptionsFromDSN.
+var xPoolOptionKeys = []string{"x-statement-timeout", "x-multi-statement"}
+
+// extractPoolOptionsFromDSN parses the x-statement-timeout/x-multi-statement
+// extensions out of dsn (in either its URL or keyword/value form) and
+// returns the poolOptions they describe, along with dsn with those two keys
+// removed so pgx never sees them. dsn is returned unmodified, with no error,
+// if it doesn't parse as a connection string at all; buildPgxPoolConfig's
+// subsequent call to pgxpool.ParseConfig is what surfaces that error to the
+// caller. An unparseable x-statement-timeout value, on the other hand, is
+// reported here rather than silently ignored.
+func extractPoolOptionsFromDSN(dsn string) (string, poolOptions, error) {
+	connString, err := parseConnectionString(dsn)
+	if err != nil {
+		return dsn, poolOptions{}, nil
+	}
+
+	var opts poolOptions
+	if connString.xStatementTimeout != "" {
+		d, err := time.ParseDuration(connString.xStatementTimeout)
+		if err != nil {
+			return "", poolOptions{}, fmt.Errorf("x-statement-timeout: %w", err)
+		}
+		opts.StatementTimeout = d
+	}
+	opts.MultiStatement = connString.xMultiStatement
+
+	return stripDSNKeys(dsn, xPoolOptionKeys), opts, nil
+}
+
+// stripDSNKeys removes the given keys from dsn, in either its URL
+// (postgres://...?key=value&...) or keyword/value (key=value key=value) form.
+func stripDSNKeys(dsn string, keys []string) string {
+	trimmed := strings.TrimSpace(dsn)
+	if strings.HasPrefix(trimmed, "postgres://") || strings.HasPrefix(trimmed, "postgresql://") {
+		parsed, err := url.Parse(dsn)
+		if err != nil {
+			return dsn
+		}
+		query := parsed.Query()
+		for _, key := range keys {
+			query.Del(key)
+		}
+		parsed.RawQuery = query.Encode()
+		return parsed.String()
+	}
+
+	pairs, err := tokenizeKeyValueConnectionString(trimmed)
+	if err != nil {
+		return dsn
+	}
+	strip := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		strip[key] = true
+	}
+
+	var b strings.Builder
+	for key, value := range pairs {
+		if strip[key] {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(quoteLibpqValue(value))
+	}
+	return b.String()
+}
@@ -2,12 +2,13 @@ package postgresql
 
 import (
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/blang/semver"
 	"github.com/hashicorp/errwrap"
-	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/hashicorp/terraform/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 )
 
 const (
@@ -22,6 +23,9 @@ const (
 
 	// defaultSshTimeout is used if there is no timeout given
 	defaultSshTimeout = 5 * time.Minute
+
+	// defaultSshKeepaliveInterval is used if there is no keepalive_interval given
+	defaultSshKeepaliveInterval = 30 * time.Second
 )
 
 // Provider returns a terraform.ResourceProvider.
@@ -32,7 +36,7 @@ func Provider() terraform.ResourceProvider {
 				Type:        schema.TypeString,
 				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("PGHOST", nil),
-				Description: "Name of PostgreSQL server address to connect to",
+				Description: "Name of PostgreSQL server address to connect to. A value starting with `/` or `@` is treated as a Unix-domain socket directory instead of a TCP host.",
 			},
 			"port": {
 				Type:        schema.TypeInt,
@@ -86,6 +90,48 @@ func Provider() terraform.ResourceProvider {
 				Optional:   true,
 				Deprecated: "Rename PostgreSQL provider `ssl_mode` attribute to `sslmode`",
 			},
+			"sslrootcert": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("PGSSLROOTCERT", nil),
+				Description: "The SSL root certificate file path used to verify the PostgreSQL server's certificate",
+			},
+			"sslcert": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("PGSSLCERT", nil),
+				Description: "The SSL client certificate file path",
+			},
+			"sslkey": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("PGSSLKEY", nil),
+				Description: "The SSL client certificate key file path",
+				Sensitive:   true,
+			},
+			"sslrootcert_inline": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The SSL root certificate, as PEM contents rather than a file path. Written to a locked-down temp file for the connection's lifetime.",
+			},
+			"sslcert_inline": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The SSL client certificate, as PEM contents rather than a file path. Written to a locked-down temp file for the connection's lifetime.",
+			},
+			"sslkey_inline": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The SSL client certificate key, as PEM contents rather than a file path. Written to a locked-down temp file for the connection's lifetime.",
+				Sensitive:   true,
+			},
+			"sslpassword": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("PGSSLPASSWORD", nil),
+				Description: "The passphrase protecting sslkey/sslkey_inline, if it's an encrypted private key. Decrypted in-memory before being written to the temp file the driver reads.",
+				Sensitive:   true,
+			},
 			"connect_timeout": {
 				Type:         schema.TypeInt,
 				Optional:     true,
@@ -100,6 +146,41 @@ func Provider() terraform.ResourceProvider {
 				Description:  "Maximum number of connections to establish to the database. Zero means unlimited.",
 				ValidateFunc: validateMaxConnections,
 			},
+			"pool_min_conns": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Minimum number of idle connections the pgx pool keeps open.",
+			},
+			"pool_max_conn_lifetime": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Maximum lifetime of a pgx pool connection, e.g. \"1h\". Connections older than this are closed and replaced.",
+			},
+			"pool_max_conn_idle_time": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Maximum amount of time a pgx pool connection may sit idle before being closed, e.g. \"30m\".",
+			},
+			"pool_health_check_period": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "How often the pgx pool checks idle connections for health, e.g. \"1m\".",
+			},
+			"statement_cache_capacity": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Number of prepared statements the pgx pool caches per connection, so long-running plans can reuse statements across resources instead of re-parsing every query.",
+			},
+			"statement_timeout": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Statement timeout applied to every connection the pgx pool opens, e.g. \"30s\". Equivalent to the x-statement-timeout connection string option.",
+			},
+			"multi_statement": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Switch the pgx pool to the simple query protocol so a single Exec/Query can carry more than one semicolon-separated statement. Equivalent to the x-multi-statement connection string option.",
+			},
 			"expected_version": {
 				Type:         schema.TypeString,
 				Optional:     true,
@@ -107,6 +188,19 @@ func Provider() terraform.ResourceProvider {
 				Description:  "Specify the expected version of PostgreSQL.",
 				ValidateFunc: validateExpectedVersion,
 			},
+			"default_isolation_level": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "read_committed",
+				Description:  "Isolation level used for transactions opened by resources. One of `read_committed`, `repeatable_read`, `serializable`.",
+				ValidateFunc: validateIsolationLevel,
+			},
+			"retry_on_serialization_failure": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+				Description: "Number of times to retry a resource operation, with exponential backoff, when Postgres returns a serialization failure (SQLSTATE 40001). Only useful with `default_isolation_level = \"serializable\"` or `\"repeatable_read\"`.",
+			},
 			"connection": {
 				Type:        schema.TypeList,
 				Optional:    true,
@@ -158,6 +252,63 @@ func Provider() terraform.ResourceProvider {
 							Default:     true,
 							Description: "Set to false to disable using ssh-agent to authenticate.",
 						},
+						"agent_identity": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The preferred identity to offer from the ssh-agent, as its comment or public key fingerprint. Only meaningful when agent is true; if unset, every identity the agent offers is tried.",
+						},
+						"bastion_private_key_passphrase": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The passphrase protecting the private key given in bastion_private_key.",
+							Sensitive:   true,
+						},
+						"keepalive_interval": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The interval between keepalive messages sent through the tunnel to keep it from being closed by a NAT or firewall. This defaults to 30 seconds.",
+						},
+					},
+				},
+			},
+			"proxy": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Reach the PostgreSQL server through a SOCKS5 or HTTP CONNECT proxy, as an alternative to the `connection` SSH bastion. Defaults to the ALL_PROXY/HTTPS_PROXY environment variables when unset.",
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"scheme": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "One of `socks5`, `socks5h`, or `http`.",
+						},
+						"host": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The proxy host.",
+						},
+						"port": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Description: "The proxy port.",
+						},
+						"username": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Username for proxy authentication.",
+						},
+						"password": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Password for proxy authentication.",
+							Sensitive:   true,
+						},
+						"allow_embedded_userinfo": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "Required to be true when username/password are set on an `http` scheme proxy, since HTTP CONNECT credentials are sent in cleartext.",
+						},
 					},
 				},
 			},
@@ -168,8 +319,19 @@ func Provider() terraform.ResourceProvider {
 			"postgresql_default_privileges": resourcePostgreSQLDefaultPrivileges(),
 			"postgresql_extension":          resourcePostgreSQLExtension(),
 			"postgresql_grant":              resourcePostgreSQLGrant(),
+			"postgresql_publication":        resourcePostgreSQLPublication(),
+			"postgresql_role_grant":         resourcePostgreSQLRoleGrant(),
+			"postgresql_role_membership":    resourcePostgreSQLRoleMembership(),
 			"postgresql_schema":             resourcePostgreSQLSchema(),
 			"postgresql_role":               resourcePostgreSQLRole(),
+			"postgresql_subscription":       resourcePostgreSQLSubscription(),
+		},
+
+		DataSourcesMap: map[string]*schema.Resource{
+			"postgresql_available_extensions": dataSourcePostgreSQLAvailableExtensions(),
+			"postgresql_extension":            dataSourcePostgreSQLExtension(),
+			"postgresql_roles":                dataSourcePostgreSQLRoles(),
+			"postgresql_schemas":               dataSourcePostgreSQLSchemas(),
 		},
 
 		ConfigureFunc: providerConfigure,
@@ -199,6 +361,13 @@ func validateMaxConnections(v interface{}, key string) (warnings []string, error
 	return
 }
 
+func validateIsolationLevel(v interface{}, key string) (warnings []string, errors []error) {
+	if _, err := isolationLevelFromString(v.(string)); err != nil {
+		errors = append(errors, err)
+	}
+	return
+}
+
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	var sslMode string
 	if sslModeRaw, ok := d.GetOk("sslmode"); ok {
@@ -212,6 +381,22 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	versionStr := d.Get("expected_version").(string)
 	version, _ := semver.Parse(versionStr)
 
+	sslRootCert, err := resolveSSLFilePath("sslrootcert", d.Get("sslrootcert").(string), d.Get("sslrootcert_inline").(string))
+	if err != nil {
+		return nil, err
+	}
+	sslCert, err := resolveSSLFilePath("sslcert", d.Get("sslcert").(string), d.Get("sslcert_inline").(string))
+	if err != nil {
+		return nil, err
+	}
+	sslKey, err := resolveSSLKeyPath(d.Get("sslkey").(string), d.Get("sslkey_inline").(string), d.Get("sslpassword").(string))
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSSLVerification(sslMode, sslRootCert); err != nil {
+		return nil, err
+	}
+
 	config := Config{
 		Host:              d.Get("host").(string),
 		Port:              d.Get("port").(int),
@@ -220,12 +405,39 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		DatabaseUsername:  d.Get("database_username").(string),
 		Superuser:         d.Get("superuser").(bool),
 		SSLMode:           sslMode,
+		SSLRootCert:       sslRootCert,
+		SSLCert:           sslCert,
+		SSLKey:            sslKey,
 		ApplicationName:   tfAppName(),
 		ConnectTimeoutSec: d.Get("connect_timeout").(int),
 		MaxConns:          d.Get("max_connections").(int),
 		ExpectedVersion:   version,
 	}
 
+	config.DefaultIsolationLevel, _ = isolationLevelFromString(d.Get("default_isolation_level").(string))
+	config.RetryOnSerializationFailure = d.Get("retry_on_serialization_failure").(int)
+
+	pool := poolOptions{
+		MinConns:               int32(d.Get("pool_min_conns").(int)),
+		StatementCacheCapacity: d.Get("statement_cache_capacity").(int),
+		MultiStatement:         d.Get("multi_statement").(bool),
+	}
+	for durationAttr, dst := range map[string]*time.Duration{
+		"pool_max_conn_lifetime":   &pool.MaxConnLifetime,
+		"pool_max_conn_idle_time":  &pool.MaxConnIdleTime,
+		"pool_health_check_period": &pool.HealthCheckPeriod,
+		"statement_timeout":        &pool.StatementTimeout,
+	} {
+		if raw := d.Get(durationAttr).(string); raw != "" {
+			parsed, err := time.ParseDuration(raw)
+			if err != nil {
+				return nil, fmt.Errorf("Error parsing %s: %w", durationAttr, err)
+			}
+			*dst = parsed
+		}
+	}
+	config.Pool = pool
+
 	// TODO configure using a hashset?
 
 	if conns, ok := d.Get("connection").([]interface{}); ok && len(conns) == 1 {
@@ -242,10 +454,46 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		//config.Timeout = conn["timeout"].(int)
 
 		config.SshAgent = conn["agent"].(bool)
+		config.SshAgentIdentity = conn["agent_identity"].(string)
+		config.SshPrivateKeyPassphrase = conn["bastion_private_key_passphrase"].(string)
+
+		config.SshKeepaliveInterval = defaultSshKeepaliveInterval
+		if keepalive := conn["keepalive_interval"].(string); keepalive != "" {
+			interval, err := time.ParseDuration(keepalive)
+			if err != nil {
+				return nil, errwrap.Wrapf("Error parsing keepalive_interval: {{err}}", err)
+			}
+			config.SshKeepaliveInterval = interval
+		}
 
 		config.Ssh = config.SshHost != ""
 	}
 
+	proxyScheme, proxyHost, proxyPort := proxyEnvDefaults(os.Getenv)
+	proxyAllowEmbeddedUserinfo := false
+	if proxies, ok := d.Get("proxy").([]interface{}); ok && len(proxies) == 1 {
+		p := proxies[0].(map[string]interface{})
+		if v := p["scheme"].(string); v != "" {
+			proxyScheme = v
+		}
+		if v := p["host"].(string); v != "" {
+			proxyHost = v
+		}
+		if v := p["port"].(int); v != 0 {
+			proxyPort = v
+		}
+		config.ProxyUsername = p["username"].(string)
+		config.ProxyPassword = p["password"].(string)
+		proxyAllowEmbeddedUserinfo = p["allow_embedded_userinfo"].(bool)
+	}
+	if proxyHost != "" {
+		spec, err := makeProxySpec(proxyScheme, proxyHost, proxyPort, config.ProxyUsername, config.ProxyPassword, proxyAllowEmbeddedUserinfo)
+		if err != nil {
+			return nil, err
+		}
+		config.Proxy = &spec
+	}
+
 	client, err := config.NewClient(d.Get("database").(string))
 	if err != nil {
 		return nil, errwrap.Wrapf("Error initializing PostgreSQL client: {{err}}", err)
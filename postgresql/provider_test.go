@@ -1,9 +1,8 @@
 package postgresql
 
 import (
-	"github.com/hashicorp/terraform/config"
-	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/hashicorp/terraform/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 	"os"
 	"testing"
 )
@@ -41,7 +40,7 @@ func testAccPreCheck(t *testing.T) {
 		t.Fatal("PGUSER must be set for acceptance tests")
 	}
 
-	err := testAccProvider.Configure(terraform.NewResourceConfig(nil))
+	err := testAccProvider.Configure(terraform.NewResourceConfigRaw(nil))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -100,12 +99,7 @@ j6FW0DcOmqu981bVAAAAGmxla3NlQERvbWluaWtzLU1CUC0yLmxvY2Fs
 		},
 	}
 
-	rc, err := config.NewRawConfig(c)
-	if err != nil {
-		t.Fatalf("err: %s", err)
-	}
-
-	err = testAccSshProvider.Configure(terraform.NewResourceConfig(rc))
+	err := testAccSshProvider.Configure(terraform.NewResourceConfigRaw(c))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,174 @@
+package postgresql
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/url"
+
+	"golang.org/x/net/proxy"
+)
+
+// proxySpec describes an egress proxy to dial PostgreSQL through, an
+// alternative to the SSH bastion in ssh_tunnel.go for shops that already
+// have a corporate SOCKS5/HTTP CONNECT proxy in place.
+type proxySpec struct {
+	Scheme   string
+	Host     string
+	Port     int
+	Username string
+	Password string
+}
+
+// makeProxySpec validates the proxy { scheme, host, port, username, password }
+// block. allowEmbeddedUserinfo gates whether an http-scheme proxy may carry
+// a username/password: HTTP CONNECT credentials are sent in cleartext
+// Proxy-Authorization unless the transport is itself wrapped in TLS, so
+// callers must opt in explicitly.
+func makeProxySpec(scheme, host string, port int, username, password string, allowEmbeddedUserinfo bool) (proxySpec, error) {
+	switch scheme {
+	case "socks5", "socks5h", "http":
+	case "":
+		return proxySpec{}, fmt.Errorf("proxy: scheme is required (one of socks5, socks5h, http)")
+	default:
+		return proxySpec{}, fmt.Errorf("proxy: unsupported scheme %q (must be one of socks5, socks5h, http)", scheme)
+	}
+
+	if host == "" {
+		return proxySpec{}, fmt.Errorf("proxy: host is required")
+	}
+
+	if port < 1 || port > 65535 {
+		return proxySpec{}, fmt.Errorf("proxy: port must be between 1 and 65535, got %d", port)
+	}
+
+	if scheme == "http" && (username != "" || password != "") && !allowEmbeddedUserinfo {
+		return proxySpec{}, fmt.Errorf("proxy: username/password on an http proxy are sent in cleartext; set allow_embedded_userinfo to confirm this is acceptable")
+	}
+
+	return proxySpec{
+		Scheme:   scheme,
+		Host:     host,
+		Port:     port,
+		Username: username,
+		Password: password,
+	}, nil
+}
+
+// proxyEnvDefaults returns scheme/host/port defaults for the proxy block
+// from HTTPS_PROXY / ALL_PROXY, the same env vars most Go HTTP clients
+// already honor, so operators don't have to configure the provider and
+// their shell environment twice.
+func proxyEnvDefaults(getenv func(string) string) (scheme, host string, port int) {
+	for _, name := range []string{"ALL_PROXY", "HTTPS_PROXY", "https_proxy"} {
+		raw := getenv(name)
+		if raw == "" {
+			continue
+		}
+		u, err := url.Parse(raw)
+		if err != nil || u.Hostname() == "" {
+			continue
+		}
+		scheme = u.Scheme
+		host = u.Hostname()
+		port = 0
+		if p := u.Port(); p != "" {
+			fmt.Sscanf(p, "%d", &port)
+		}
+		return
+	}
+	return "", "", 0
+}
+
+// dialThroughProxy returns a dial function that reaches addr (the real
+// PostgreSQL host:port) through the configured proxy, for use in place of
+// net.Dial when Config has a proxy configured.
+func dialThroughProxy(spec proxySpec) (func(ctx context.Context, network, addr string) (net.Conn, error), error) {
+	proxyAddr := net.JoinHostPort(spec.Host, fmt.Sprintf("%d", spec.Port))
+
+	switch spec.Scheme {
+	case "socks5", "socks5h":
+		var auth *proxy.Auth
+		if spec.Username != "" {
+			auth = &proxy.Auth{User: spec.Username, Password: spec.Password}
+		}
+		dialer, err := proxy.SOCKS5("tcp", proxyAddr, auth, proxy.Direct)
+		if err != nil {
+			return nil, fmt.Errorf("Error building SOCKS5 dialer for %s: %w", proxyAddr, err)
+		}
+		return func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return dialer.Dial(network, addr)
+		}, nil
+
+	case "http":
+		return func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return dialHTTPConnect(ctx, proxyAddr, addr, spec.Username, spec.Password)
+		}, nil
+	}
+
+	return nil, fmt.Errorf("proxy: unsupported scheme %q", spec.Scheme)
+}
+
+// dialHTTPConnect opens a TCP connection to proxyAddr and issues an HTTP
+// CONNECT request for target, returning the tunneled connection once the
+// proxy replies 200.
+func dialHTTPConnect(ctx context.Context, proxyAddr, target, username, password string) (net.Conn, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", proxyAddr)
+	if err != nil {
+		return nil, fmt.Errorf("Error dialing HTTP proxy %s: %w", proxyAddr, err)
+	}
+
+	req := fmt.Sprintf("CONNECT %s HTTP/1.1\r\nHost: %s\r\n", target, target)
+	if username != "" {
+		req += fmt.Sprintf("Proxy-Authorization: Basic %s\r\n", basicAuth(username, password))
+	}
+	req += "\r\n"
+
+	if _, err := conn.Write([]byte(req)); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("Error writing CONNECT request to %s: %w", proxyAddr, err)
+	}
+
+	resp, err := readHTTPStatusLine(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("Error reading CONNECT response from %s: %w", proxyAddr, err)
+	}
+	if resp != 200 {
+		conn.Close()
+		return nil, fmt.Errorf("HTTP proxy %s refused CONNECT to %s: status %d", proxyAddr, target, resp)
+	}
+
+	return conn, nil
+}
+
+func readHTTPStatusLine(conn net.Conn) (int, error) {
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return 0, err
+	}
+
+	var httpVersion string
+	var status int
+	if _, err := fmt.Sscanf(line, "%s %d", &httpVersion, &status); err != nil {
+		return 0, fmt.Errorf("malformed status line %q", line)
+	}
+
+	// Drain the rest of the proxy's response headers.
+	for {
+		l, err := reader.ReadString('\n')
+		if err != nil || l == "\r\n" {
+			break
+		}
+	}
+
+	return status, nil
+}
+
+func basicAuth(username, password string) string {
+	return base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+}
@@ -0,0 +1,39 @@
+package postgresql
+
+import "testing"
+
+func TestMakeProxySpec(t *testing.T) {
+	cases := []struct {
+		name        string
+		scheme      string
+		host        string
+		port        int
+		username    string
+		password    string
+		allowUser   bool
+		expectError bool
+	}{
+		{name: "valid socks5", scheme: "socks5", host: "proxy.internal", port: 1080},
+		{name: "valid socks5h", scheme: "socks5h", host: "proxy.internal", port: 1080},
+		{name: "valid http", scheme: "http", host: "proxy.internal", port: 3128},
+		{name: "missing scheme", scheme: "", host: "proxy.internal", port: 1080, expectError: true},
+		{name: "unknown scheme", scheme: "ftp", host: "proxy.internal", port: 21, expectError: true},
+		{name: "missing host", scheme: "socks5", host: "", port: 1080, expectError: true},
+		{name: "port too low", scheme: "socks5", host: "proxy.internal", port: 0, expectError: true},
+		{name: "port too high", scheme: "socks5", host: "proxy.internal", port: 70000, expectError: true},
+		{name: "http with userinfo disallowed", scheme: "http", host: "proxy.internal", port: 3128, username: "u", password: "p", expectError: true},
+		{name: "http with userinfo allowed", scheme: "http", host: "proxy.internal", port: 3128, username: "u", password: "p", allowUser: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := makeProxySpec(c.scheme, c.host, c.port, c.username, c.password, c.allowUser)
+			if c.expectError && err == nil {
+				t.Errorf("expected an error, got nil")
+			}
+			if !c.expectError && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		})
+	}
+}
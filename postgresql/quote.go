@@ -0,0 +1,22 @@
+package postgresql
+
+import "github.com/hashicorp/terraform-provider-postgresql/postgresql/internal/quote"
+
+// quoteIdentifier double-quotes a single SQL identifier, escaping embedded
+// double quotes, the same way lib/pq.QuoteIdentifier does. jackc/pgx/v5
+// doesn't expose an equivalent helper (pgx.Identifier.Sanitize handles
+// dotted multi-part names, not a single already-qualified piece), so this is
+// the drop-in replacement as call sites move off pq.QuoteIdentifier. It
+// forwards to internal/quote so postgresql/internal/acl can share the same
+// logic without importing this package and creating a cycle.
+func quoteIdentifier(name string) string {
+	return quote.Identifier(name)
+}
+
+// quoteLiteral single-quotes a SQL string literal, escaping embedded single
+// quotes and backslashes the same way lib/pq.QuoteLiteral does (doubling
+// single quotes, and wrapping in an E'...' escape string if the value
+// contains a backslash).
+func quoteLiteral(literal string) string {
+	return quote.Literal(literal)
+}
@@ -0,0 +1,353 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+
+	acl "github.com/hashicorp/terraform-provider-postgresql/postgresql/internal/acl"
+)
+
+var allowedDefaultPrivilegesObjectTypes = []string{
+	"table",
+	"sequence",
+	"function",
+	"type",
+	"schema",
+}
+
+// defaultACLObjectTypes maps the object_type attribute to the single letter
+// pg_default_acl.defaclobjtype uses to identify it.
+var defaultACLObjectTypes = map[string]string{
+	"table":    "r",
+	"sequence": "S",
+	"function": "f",
+	"type":     "T",
+	"schema":   "n",
+}
+
+func resourcePostgreSQLDefaultPrivileges() *schema.Resource {
+	return &schema.Resource{
+		Create: resourcePostgreSQLDefaultPrivilegesCreate,
+		// As create revokes and grants we can use it to update too
+		Update: resourcePostgreSQLDefaultPrivilegesCreate,
+		Read:   resourcePostgreSQLDefaultPrivilegesRead,
+		Delete: resourcePostgreSQLDefaultPrivilegesDelete,
+
+		Schema: map[string]*schema.Schema{
+			"role": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the role to grant default privileges on",
+			},
+			"owner": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The role whose future objects will be granted default privileges (FOR ROLE)",
+			},
+			"database": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The database to grant default privileges on for this role",
+			},
+			"schema": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The database schema to grant default privileges on for this role. If unspecified, the defaults apply cluster-wide for the owner.",
+			},
+			"object_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(allowedDefaultPrivilegesObjectTypes, false),
+				Description:  "The PostgreSQL object type to set the default privileges on (one of: " + strings.Join(allowedDefaultPrivilegesObjectTypes, ", ") + ")",
+			},
+			"privileges": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				MinItems:    1,
+				Description: "The list of privileges to apply as default privileges",
+			},
+			"with_grant_option": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Permit the grant recipient to grant it to others",
+			},
+		},
+	}
+}
+
+func resourcePostgreSQLDefaultPrivilegesCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	if !client.featureSupported(featurePrivileges) {
+		return fmt.Errorf(
+			"postgresql_default_privileges resource is not supported for this Postgres version (%s)",
+			client.version,
+		)
+	}
+
+	if err := validatePrivileges(d); err != nil {
+		return err
+	}
+
+	client.catalogLock.Lock()
+	defer client.catalogLock.Unlock()
+
+	database := d.Get("database").(string)
+
+	txn, err := startTransaction(client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if err := withRoleSet(txn, client, d.Get("owner").(string), func() error {
+		// Revoke before granting so reducing privileges also works.
+		if err := revokeDefaultPrivileges(txn, d); err != nil {
+			return err
+		}
+		return grantDefaultPrivileges(txn, d)
+	}); err != nil {
+		return err
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId(generateDefaultPrivilegesID(d))
+
+	return resourcePostgreSQLDefaultPrivilegesReadImpl(d, meta)
+}
+
+func resourcePostgreSQLDefaultPrivilegesRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	if !client.featureSupported(featurePrivileges) {
+		return fmt.Errorf(
+			"postgresql_default_privileges resource is not supported for this Postgres version (%s)",
+			client.version,
+		)
+	}
+
+	client.catalogLock.RLock()
+	defer client.catalogLock.RUnlock()
+
+	return resourcePostgreSQLDefaultPrivilegesReadImpl(d, meta)
+}
+
+func resourcePostgreSQLDefaultPrivilegesReadImpl(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	txn, err := startTransaction(client, d.Get("database").(string))
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	// pg_default_acl.defaclacl is the raw aclitem array; parse it with the
+	// internal acl package instead of aclexplode()-ing it in SQL (see
+	// resource_postgresql_grant.go). No row at all means no defaults have
+	// ever been set for this role/namespace/object type, which is the same
+	// as an empty ACL.
+	query := `
+SELECT COALESCE(da.defaclacl, '{}'::aclitem[])::TEXT[]
+FROM pg_default_acl da
+JOIN pg_roles r ON r.oid = da.defaclrole
+LEFT JOIN pg_namespace n ON n.oid = da.defaclnamespace
+WHERE r.rolname = $1
+  AND da.defaclobjtype = $2
+  AND coalesce(n.nspname, '') = $3
+`
+	objectType := d.Get("object_type").(string)
+	var rawACL []string
+	err = txn.QueryRow(query, d.Get("owner").(string), defaultACLObjectTypes[objectType], d.Get("schema").(string)).Scan(&rawACL)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("could not read default privileges: %w", err)
+	}
+
+	merged, err := mergeRoleACLItems(rawACL, d.Get("role").(string))
+	if err != nil {
+		return fmt.Errorf("could not read default privileges: %w", err)
+	}
+
+	var privileges []string
+	switch objectType {
+	case "schema":
+		observed, err := acl.NewSchema(merged)
+		if err != nil {
+			return fmt.Errorf("could not read default privileges: %w", err)
+		}
+		privileges = observed.PrivilegeKeywords()
+	case "function":
+		observed, err := acl.NewFunction(merged)
+		if err != nil {
+			return fmt.Errorf("could not read default privileges: %w", err)
+		}
+		privileges = observed.PrivilegeKeywords()
+	case "sequence":
+		observed, err := acl.NewSequence(merged)
+		if err != nil {
+			return fmt.Errorf("could not read default privileges: %w", err)
+		}
+		privileges = observed.PrivilegeKeywords()
+	case "type":
+		observed, err := acl.NewType(merged)
+		if err != nil {
+			return fmt.Errorf("could not read default privileges: %w", err)
+		}
+		privileges = observed.PrivilegeKeywords()
+	default:
+		observed, err := acl.NewTable(merged)
+		if err != nil {
+			return fmt.Errorf("could not read default privileges: %w", err)
+		}
+		privileges = observed.PrivilegeKeywords()
+	}
+
+	d.Set("privileges", privileges)
+	d.SetId(generateDefaultPrivilegesID(d))
+
+	return nil
+}
+
+func resourcePostgreSQLDefaultPrivilegesDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	if !client.featureSupported(featurePrivileges) {
+		return fmt.Errorf(
+			"postgresql_default_privileges resource is not supported for this Postgres version (%s)",
+			client.version,
+		)
+	}
+
+	client.catalogLock.Lock()
+	defer client.catalogLock.Unlock()
+
+	txn, err := startTransaction(client, d.Get("database").(string))
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if err := withRoleSet(txn, client, d.Get("owner").(string), func() error {
+		return revokeDefaultPrivileges(txn, d)
+	}); err != nil {
+		return err
+	}
+
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+func grantDefaultPrivileges(txn *sql.Tx, d *schema.ResourceData) error {
+	privileges := getStringsFromSet(d, "privileges")
+	if len(privileges) == 0 {
+		return nil
+	}
+
+	query := fmt.Sprintf(
+		"ALTER DEFAULT PRIVILEGES FOR ROLE %s %sGRANT %s ON %s TO %s",
+		quoteIdentifier(d.Get("owner").(string)),
+		inSchemaClause(d),
+		strings.Join(privileges, ","),
+		defaultPrivilegesObjectClause(d),
+		quoteIdentifier(d.Get("role").(string)),
+	)
+
+	if d.Get("with_grant_option").(bool) {
+		query += " WITH GRANT OPTION"
+	}
+
+	_, err := txn.Exec(query)
+	return err
+}
+
+func revokeDefaultPrivileges(txn *sql.Tx, d *schema.ResourceData) error {
+	query := fmt.Sprintf(
+		"ALTER DEFAULT PRIVILEGES FOR ROLE %s %sREVOKE ALL PRIVILEGES ON %s FROM %s",
+		quoteIdentifier(d.Get("owner").(string)),
+		inSchemaClause(d),
+		defaultPrivilegesObjectClause(d),
+		quoteIdentifier(d.Get("role").(string)),
+	)
+
+	if _, err := txn.Exec(query); err != nil {
+		return fmt.Errorf("could not execute revoke default privileges query: %w", err)
+	}
+	return nil
+}
+
+func inSchemaClause(d *schema.ResourceData) string {
+	schemaName := d.Get("schema").(string)
+	if schemaName == "" {
+		return ""
+	}
+	return fmt.Sprintf("IN SCHEMA %s ", quoteIdentifier(schemaName))
+}
+
+func defaultPrivilegesObjectClause(d *schema.ResourceData) string {
+	return strings.ToUpper(d.Get("object_type").(string)) + "S"
+}
+
+// withRoleSet runs fn with the current session's role set to roleName,
+// resetting it on the way out. ALTER DEFAULT PRIVILEGES affects the defaults
+// for the current role, so it must be executed as the owning role. If the
+// connected user isn't already a member of roleName, it's granted
+// membership for the duration of fn (the same grantRoleMembership /
+// revokeRoleMembership dance resource_postgresql_schema.go uses so a
+// non-superuser Terraform user can manage defaults owned by another role)
+// and revoked again afterwards.
+func withRoleSet(txn *sql.Tx, client *Client, roleName string, fn func() error) error {
+	currentUser := client.config.getDatabaseUsername()
+
+	ownerGranted, err := grantRoleMembership(txn, roleName, currentUser)
+	if err != nil {
+		return fmt.Errorf("could not grant membership in %s to %s: %w", roleName, currentUser, err)
+	}
+
+	if _, err := txn.Exec(fmt.Sprintf("SET ROLE %s", quoteIdentifier(roleName))); err != nil {
+		return fmt.Errorf("could not set role %s before altering default privileges: %w", roleName, err)
+	}
+
+	fnErr := fn()
+
+	if _, err := txn.Exec("RESET ROLE"); err != nil {
+		return fmt.Errorf("could not reset role after altering default privileges: %w", err)
+	}
+
+	if ownerGranted {
+		if err := revokeRoleMembership(txn, roleName, currentUser); err != nil {
+			return fmt.Errorf("could not revoke membership in %s from %s: %w", roleName, currentUser, err)
+		}
+	}
+
+	return fnErr
+}
+
+func generateDefaultPrivilegesID(d *schema.ResourceData) string {
+	return strings.Join([]string{
+		d.Get("role").(string),
+		d.Get("owner").(string),
+		d.Get("database").(string),
+		d.Get("schema").(string),
+		d.Get("object_type").(string),
+	}, "_")
+}
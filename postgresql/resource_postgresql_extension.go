@@ -9,15 +9,23 @@ import (
 	"strings"
 
 	"github.com/hashicorp/errwrap"
-	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/lib/pq"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 )
 
 const (
-	extNameAttr     = "name"
-	extSchemaAttr   = "schema"
-	extVersionAttr  = "version"
-	extDatabaseAttr = "database"
+	extNameAttr          = "name"
+	extSchemaAttr        = "schema"
+	extVersionAttr       = "version"
+	extDatabaseAttr      = "database"
+	extIfNotExistsAttr   = "if_not_exists"
+	extCreateCascadeAttr = "create_cascade"
+	extIfExistsAttr      = "if_exists"
+	extDropCascadeAttr   = "drop_cascade"
+	extVersionPolicyAttr = "version_policy"
+
+	extVersionPolicyPinned       = "pinned"
+	extVersionPolicyLatest       = "latest"
+	extVersionPolicyTrackDefault = "track_default"
 )
 
 func resourcePostgreSQLExtension() *schema.Resource {
@@ -55,10 +63,53 @@ func resourcePostgreSQLExtension() *schema.Resource {
 				Computed:    true,
 				Description: "Sets the database to add the extension to",
 			},
+			extIfNotExistsAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "When true, issues CREATE EXTENSION IF NOT EXISTS so an extension already present in the database is not an error. When false, a pre-existing extension causes Create to fail.",
+			},
+			extCreateCascadeAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "When true, before creating this extension, also create (in the same transaction) every extension pg_available_extensions.requires lists for it, recursively, so dependent extension stacks such as postgis/postgis_topology/postgis_raster can be declared with a single resource",
+			},
+			extIfExistsAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "When true, issues DROP EXTENSION IF EXISTS so deleting an already-absent extension is not an error",
+			},
+			extDropCascadeAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "When true, issues DROP EXTENSION ... CASCADE, also dropping objects (views, foreign tables, etc.) that depend on the extension",
+			},
+			extVersionPolicyAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     extVersionPolicyPinned,
+				Description: "How `version` is reconciled on Read: `pinned` (default) only reports the installed version, so a newer available version is never reported as drift; `latest` reports the newest version pg_available_extension_versions knows about for this extension, so a Postgres package upgrade that ships a newer version triggers `ALTER EXTENSION ... UPDATE` on the next apply; `track_default` does the same but follows pg_available_extensions.default_version instead of the newest version.",
+				ValidateFunc: validateExtVersionPolicy,
+			},
 		},
 	}
 }
 
+func validateExtVersionPolicy(v interface{}, key string) (warnings []string, errors []error) {
+	switch v.(string) {
+	case extVersionPolicyPinned, extVersionPolicyLatest, extVersionPolicyTrackDefault:
+	default:
+		errors = append(errors, fmt.Errorf(
+			"%s must be one of %q, %q or %q", key,
+			extVersionPolicyPinned, extVersionPolicyLatest, extVersionPolicyTrackDefault,
+		))
+	}
+	return
+}
+
 func resourcePostgreSQLExtensionCreate(d *schema.ResourceData, meta interface{}) error {
 	c := meta.(*Client)
 
@@ -73,25 +124,33 @@ func resourcePostgreSQLExtensionCreate(d *schema.ResourceData, meta interface{})
 	defer c.catalogLock.Unlock()
 
 	extName := d.Get(extNameAttr).(string)
+	database := getDatabaseForExtension(d, c)
 
-	b := bytes.NewBufferString("CREATE EXTENSION IF NOT EXISTS ")
-	fmt.Fprint(b, pq.QuoteIdentifier(extName))
+	txn, err := startTransaction(c, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
 
-	if v, ok := d.GetOk(extSchemaAttr); ok {
-		fmt.Fprint(b, " SCHEMA ", pq.QuoteIdentifier(v.(string)))
+	if d.Get(extCreateCascadeAttr).(bool) {
+		if err := createRequiredExtensions(txn, extName, map[string]bool{}); err != nil {
+			return err
+		}
 	}
 
-	if v, ok := d.GetOk(extVersionAttr); ok {
-		fmt.Fprint(b, " VERSION ", pq.QuoteIdentifier(v.(string)))
+	b := bytes.NewBufferString("CREATE EXTENSION ")
+	if d.Get(extIfNotExistsAttr).(bool) {
+		fmt.Fprint(b, "IF NOT EXISTS ")
 	}
+	fmt.Fprint(b, quoteIdentifier(extName))
 
-	database := getDatabaseForExtension(d, c)
+	if v, ok := d.GetOk(extSchemaAttr); ok {
+		fmt.Fprint(b, " SCHEMA ", quoteIdentifier(v.(string)))
+	}
 
-	txn, err := startTransaction(c, database)
-	if err != nil {
-		return err
+	if v, ok := d.GetOk(extVersionAttr); ok {
+		fmt.Fprint(b, " VERSION ", quoteIdentifier(v.(string)))
 	}
-	defer deferredRollback(txn)
 
 	sql := b.String()
 	if _, err := txn.Exec(sql); err != nil {
@@ -180,15 +239,49 @@ func resourcePostgreSQLExtensionReadImpl(d *schema.ResourceData, meta interface{
 		return errwrap.Wrapf("Error reading extension: {{err}}", err)
 	}
 
+	targetVersion, err := targetExtensionVersion(txn, extName, d.Get(extVersionPolicyAttr).(string), extVersion)
+	if err != nil {
+		return err
+	}
+
 	d.Set(extNameAttr, extName)
 	d.Set(extSchemaAttr, extSchema)
-	d.Set(extVersionAttr, extVersion)
+	d.Set(extVersionAttr, targetVersion)
 	d.Set(extDatabaseAttr, database)
 	d.SetId(generateExtensionID(d, meta.(*Client)))
 
 	return nil
 }
 
+// targetExtensionVersion resolves the version Read should report for
+// extVersionAttr under the given version_policy. For `pinned` this is just
+// the installed version, so a newer available version is never surfaced as
+// drift. For `latest` and
+// `track_default` it looks up the newest / default version Postgres
+// currently advertises; reporting that instead of the installed version
+// means a stale installation shows up as drift, and the subsequent Update
+// drives setExtVersion's ALTER EXTENSION ... UPDATE TO the resolved target.
+func targetExtensionVersion(txn *sql.Tx, extName, policy, installedVersion string) (string, error) {
+	switch policy {
+	case extVersionPolicyLatest:
+		var latest string
+		query := "SELECT max(version) FROM pg_catalog.pg_available_extension_versions WHERE name = $1"
+		if err := txn.QueryRow(query, extName).Scan(&latest); err != nil {
+			return "", errwrap.Wrapf(fmt.Sprintf("Error looking up latest version for extension %s: {{err}}", extName), err)
+		}
+		return latest, nil
+	case extVersionPolicyTrackDefault:
+		var defaultVersion string
+		query := "SELECT default_version FROM pg_catalog.pg_available_extensions WHERE name = $1"
+		if err := txn.QueryRow(query, extName).Scan(&defaultVersion); err != nil {
+			return "", errwrap.Wrapf(fmt.Sprintf("Error looking up default version for extension %s: {{err}}", extName), err)
+		}
+		return defaultVersion, nil
+	default:
+		return installedVersion, nil
+	}
+}
+
 func resourcePostgreSQLExtensionDelete(d *schema.ResourceData, meta interface{}) error {
 	c := meta.(*Client)
 
@@ -211,8 +304,30 @@ func resourcePostgreSQLExtensionDelete(d *schema.ResourceData, meta interface{})
 	}
 	defer deferredRollback(txn)
 
-	sql := fmt.Sprintf("DROP EXTENSION %s", pq.QuoteIdentifier(extName))
-	if _, err := txn.Exec(sql); err != nil {
+	dropCascade := d.Get(extDropCascadeAttr).(bool)
+	if !dropCascade {
+		dependents, err := dependentObjects(txn, extName)
+		if err != nil {
+			return err
+		}
+		if len(dependents) > 0 {
+			return fmt.Errorf(
+				"cannot drop extension %s because other objects depend on it: %s (set drop_cascade = true to drop them along with the extension)",
+				extName, strings.Join(dependents, ", "),
+			)
+		}
+	}
+
+	b := bytes.NewBufferString("DROP EXTENSION ")
+	if d.Get(extIfExistsAttr).(bool) {
+		fmt.Fprint(b, "IF EXISTS ")
+	}
+	fmt.Fprint(b, quoteIdentifier(extName))
+	if dropCascade {
+		fmt.Fprint(b, " CASCADE")
+	}
+
+	if _, err := txn.Exec(b.String()); err != nil {
 		return err
 	}
 
@@ -275,7 +390,7 @@ func setExtSchema(txn *sql.Tx, d *schema.ResourceData) error {
 	}
 
 	sql := fmt.Sprintf("ALTER EXTENSION %s SET SCHEMA %s",
-		pq.QuoteIdentifier(extName), pq.QuoteIdentifier(n))
+		quoteIdentifier(extName), quoteIdentifier(n))
 	if _, err := txn.Exec(sql); err != nil {
 		return errwrap.Wrapf("Error updating extension SCHEMA: {{err}}", err)
 	}
@@ -291,12 +406,12 @@ func setExtVersion(txn *sql.Tx, d *schema.ResourceData) error {
 	extName := d.Get(extNameAttr).(string)
 
 	b := bytes.NewBufferString("ALTER EXTENSION ")
-	fmt.Fprintf(b, "%s UPDATE", pq.QuoteIdentifier(extName))
+	fmt.Fprintf(b, "%s UPDATE", quoteIdentifier(extName))
 
 	_, nraw := d.GetChange(extVersionAttr)
 	n := nraw.(string)
 	if n != "" {
-		fmt.Fprintf(b, " TO %s", pq.QuoteIdentifier(n))
+		fmt.Fprintf(b, " TO %s", quoteIdentifier(n))
 	}
 
 	sql := b.String()
@@ -307,6 +422,68 @@ func setExtVersion(txn *sql.Tx, d *schema.ResourceData) error {
 	return nil
 }
 
+// createRequiredExtensions looks up extName's declared requirements in
+// pg_available_extensions and CREATE EXTENSION IF NOT EXISTS's each one in
+// turn before the caller creates extName itself, recursing so multi-level
+// stacks (e.g. postgis_topology requiring postgis) are fully satisfied.
+// visited guards against a cycle turning this into infinite recursion.
+func createRequiredExtensions(txn *sql.Tx, extName string, visited map[string]bool) error {
+	if visited[extName] {
+		return nil
+	}
+	visited[extName] = true
+
+	var requires []string
+	query := "SELECT COALESCE(requires, '{}') FROM pg_catalog.pg_available_extensions WHERE name = $1"
+	if err := txn.QueryRow(query, extName).Scan(&requires); err != nil {
+		return errwrap.Wrapf(fmt.Sprintf("Error looking up requirements for extension %s: {{err}}", extName), err)
+	}
+
+	for _, required := range requires {
+		if err := createRequiredExtensions(txn, required, visited); err != nil {
+			return err
+		}
+
+		sql := fmt.Sprintf("CREATE EXTENSION IF NOT EXISTS %s", quoteIdentifier(required))
+		if _, err := txn.Exec(sql); err != nil {
+			return errwrap.Wrapf(fmt.Sprintf("Error creating required extension %s: {{err}}", required), err)
+		}
+	}
+
+	return nil
+}
+
+// dependentObjects returns a human-readable description of every object
+// that pg_depend records as depending on extName, so Delete can report a
+// friendlier error than Postgres's own "cannot drop extension ... because
+// other objects depend on it" before ever issuing a DROP that would fail.
+func dependentObjects(txn *sql.Tx, extName string) ([]string, error) {
+	query := `
+		SELECT pg_catalog.pg_describe_object(d.classid, d.objid, d.objsubid)
+		FROM pg_catalog.pg_depend d
+		JOIN pg_catalog.pg_extension e ON e.oid = d.refobjid
+		WHERE e.extname = $1
+		  AND d.deptype = 'n'
+		  AND d.classid != 'pg_catalog.pg_extension'::regclass
+	`
+	rows, err := txn.Query(query, extName)
+	if err != nil {
+		return nil, errwrap.Wrapf(fmt.Sprintf("Error checking dependents of extension %s: {{err}}", extName), err)
+	}
+	defer rows.Close()
+
+	var dependents []string
+	for rows.Next() {
+		var description string
+		if err := rows.Scan(&description); err != nil {
+			return nil, errwrap.Wrapf("Error scanning dependent object: {{err}}", err)
+		}
+		dependents = append(dependents, description)
+	}
+
+	return dependents, rows.Err()
+}
+
 func getDatabaseForExtension(d *schema.ResourceData, client *Client) string {
 	database := client.databaseName
 	if v, ok := d.GetOk(extDatabaseAttr); ok {
@@ -0,0 +1,200 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+const (
+	roleGrantRoleAttr            = "role"
+	roleGrantGrantRoleAttr       = "grant_role"
+	roleGrantWithAdminOptionAttr = "with_admin_option"
+	roleGrantWithInheritAttr     = "with_inherit_option"
+	roleGrantWithSetAttr         = "with_set_option"
+)
+
+func resourcePostgreSQLRoleGrant() *schema.Resource {
+	return &schema.Resource{
+		Create: resourcePostgreSQLRoleGrantCreate,
+		Read:   resourcePostgreSQLRoleGrantRead,
+		Delete: resourcePostgreSQLRoleGrantDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			roleGrantRoleAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The role to grant membership in",
+			},
+			roleGrantGrantRoleAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The role that is granted membership in `role`",
+			},
+			roleGrantWithAdminOptionAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Permit the grant recipient to grant the role to others and to revoke it",
+			},
+			roleGrantWithInheritAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Whether the grant recipient automatically has the privileges of the granted role (PostgreSQL 16+; ignored on older versions)",
+			},
+			roleGrantWithSetAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Whether the grant recipient can SET ROLE to the granted role (PostgreSQL 16+; ignored on older versions)",
+			},
+		},
+	}
+}
+
+func resourcePostgreSQLRoleGrantCreate(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*Client)
+	c.catalogLock.Lock()
+	defer c.catalogLock.Unlock()
+
+	role := d.Get(roleGrantRoleAttr).(string)
+	grantRole := d.Get(roleGrantGrantRoleAttr).(string)
+
+	query := fmt.Sprintf(
+		"GRANT %s TO %s",
+		quoteIdentifier(role),
+		quoteIdentifier(grantRole),
+	)
+
+	var options []string
+	if d.Get(roleGrantWithAdminOptionAttr).(bool) {
+		options = append(options, "ADMIN OPTION")
+	}
+	if c.featureSupported(featureRoleOptsInAuthMembers) {
+		options = append(options, fmt.Sprintf("INHERIT %t", d.Get(roleGrantWithInheritAttr).(bool)))
+		options = append(options, fmt.Sprintf("SET %t", d.Get(roleGrantWithSetAttr).(bool)))
+	}
+	if len(options) > 0 {
+		query += " WITH " + strings.Join(options, ", ")
+	}
+
+	if _, err := c.DB().Exec(query); err != nil {
+		return fmt.Errorf("Error granting role %s to %s: %w", role, grantRole, err)
+	}
+
+	d.SetId(generateRoleGrantID(role, grantRole))
+	return resourcePostgreSQLRoleGrantReadImpl(d, meta)
+}
+
+func resourcePostgreSQLRoleGrantRead(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*Client)
+	c.catalogLock.RLock()
+	defer c.catalogLock.RUnlock()
+
+	return resourcePostgreSQLRoleGrantReadImpl(d, meta)
+}
+
+func resourcePostgreSQLRoleGrantReadImpl(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*Client)
+
+	// Role names may themselves contain the ID's "_" delimiter, so the ID is
+	// only parsed when state doesn't already carry both names (i.e. on import).
+	role := d.Get(roleGrantRoleAttr).(string)
+	grantRole := d.Get(roleGrantGrantRoleAttr).(string)
+	if role == "" || grantRole == "" {
+		var err error
+		role, grantRole, err = splitRoleGrantID(d.Id())
+		if err != nil {
+			return err
+		}
+	}
+
+	var adminOption bool
+	var inheritOption, setOption sql.NullBool
+
+	var query string
+	if c.featureSupported(featureRoleOptsInAuthMembers) {
+		query = `
+SELECT am.admin_option, am.inherit_option, am.set_option
+FROM pg_auth_members am
+JOIN pg_roles r ON r.oid = am.roleid
+JOIN pg_roles m ON m.oid = am.member
+WHERE r.rolname = $1 AND m.rolname = $2
+`
+	} else {
+		query = `
+SELECT am.admin_option, NULL, NULL
+FROM pg_auth_members am
+JOIN pg_roles r ON r.oid = am.roleid
+JOIN pg_roles m ON m.oid = am.member
+WHERE r.rolname = $1 AND m.rolname = $2
+`
+	}
+
+	err := c.DB().QueryRow(query, role, grantRole).Scan(&adminOption, &inheritOption, &setOption)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] Role grant of %s to %s not found, removing from state", role, grantRole)
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading role grant of %s to %s: %w", role, grantRole, err)
+	}
+
+	d.Set(roleGrantRoleAttr, role)
+	d.Set(roleGrantGrantRoleAttr, grantRole)
+	d.Set(roleGrantWithAdminOptionAttr, adminOption)
+	if inheritOption.Valid {
+		d.Set(roleGrantWithInheritAttr, inheritOption.Bool)
+	}
+	if setOption.Valid {
+		d.Set(roleGrantWithSetAttr, setOption.Bool)
+	}
+
+	return nil
+}
+
+func resourcePostgreSQLRoleGrantDelete(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*Client)
+	c.catalogLock.Lock()
+	defer c.catalogLock.Unlock()
+
+	role := d.Get(roleGrantRoleAttr).(string)
+	grantRole := d.Get(roleGrantGrantRoleAttr).(string)
+
+	query := fmt.Sprintf(
+		"REVOKE %s FROM %s",
+		quoteIdentifier(role),
+		quoteIdentifier(grantRole),
+	)
+	if _, err := c.DB().Exec(query); err != nil {
+		return fmt.Errorf("Error revoking role %s from %s: %w", role, grantRole, err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func generateRoleGrantID(role, grantRole string) string {
+	return strings.Join([]string{role, grantRole}, "_")
+}
+
+func splitRoleGrantID(id string) (string, string, error) {
+	parts := strings.Split(id, "_")
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("role grant ID %s has not the expected format 'role_grantRole'", id)
+	}
+	return parts[0], parts[1], nil
+}
@@ -0,0 +1,110 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccPostgresqlRoleGrant_Basic(t *testing.T) {
+	rString := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+	config := fmt.Sprintf(testAccPostgresqlRoleGrantConfig, rString, rString)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlRoleGrantDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlRoleGrantExists("postgresql_role_grant.grant"),
+					resource.TestCheckResourceAttr("postgresql_role_grant.grant", "with_admin_option", "true"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckPostgresqlRoleGrantExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Resource not found: %s", n)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		c := testAccProvider.Meta().(*Client)
+		var exists bool
+		err := c.DB().QueryRow(
+			"SELECT TRUE FROM pg_auth_members am JOIN pg_roles r ON r.oid = am.roleid JOIN pg_roles m ON m.oid = am.member WHERE r.rolname = $1 AND m.rolname = $2",
+			rs.Primary.Attributes["role"], rs.Primary.Attributes["grant_role"],
+		).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("Error checking role grant: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("Role grant not found")
+		}
+		return nil
+	}
+}
+
+func testAccCheckPostgresqlRoleGrantDestroy(s *terraform.State) error {
+	c := testAccProvider.Meta().(*Client)
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "postgresql_role_grant" {
+			continue
+		}
+
+		var exists bool
+		err := c.DB().QueryRow(
+			"SELECT TRUE FROM pg_auth_members am JOIN pg_roles r ON r.oid = am.roleid JOIN pg_roles m ON m.oid = am.member WHERE r.rolname = $1 AND m.rolname = $2",
+			rs.Primary.Attributes["role"], rs.Primary.Attributes["grant_role"],
+		).Scan(&exists)
+		switch {
+		case err == sql.ErrNoRows:
+			continue
+		case err != nil:
+			return fmt.Errorf("Error checking role grant destroy: %w", err)
+		}
+		if exists {
+			return fmt.Errorf("Role grant still exists after destroy")
+		}
+
+		// Destroying the grant must not have destroyed either role.
+		for _, roleName := range []string{rs.Primary.Attributes["role"], rs.Primary.Attributes["grant_role"]} {
+			var roleExists bool
+			if err := c.DB().QueryRow("SELECT TRUE FROM pg_roles WHERE rolname = $1", roleName).Scan(&roleExists); err != nil {
+				return fmt.Errorf("Role %s was unexpectedly destroyed along with its grant: %w", roleName, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+const testAccPostgresqlRoleGrantConfig = `
+resource "postgresql_role" "group" {
+  name = "tf_role_grant_group_%s"
+}
+
+resource "postgresql_role" "member" {
+  name = "tf_role_grant_member_%s"
+}
+
+resource "postgresql_role_grant" "grant" {
+  role              = postgresql_role.group.name
+  grant_role        = postgresql_role.member.name
+  with_admin_option = true
+}
+`
@@ -1,12 +1,18 @@
 package postgresql
 
 import (
-	"database/sql"
+	"context"
 	"fmt"
 	"log"
 
 	"github.com/hashicorp/errwrap"
-	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+const (
+	roleMembershipRoleAttr            = "role"
+	roleMembershipMembersAttr         = "members"
+	roleMembershipWithAdminOptionAttr = "with_admin_option"
 )
 
 func resourcePostgreSQLRoleMembership() *schema.Resource {
@@ -15,23 +21,29 @@ func resourcePostgreSQLRoleMembership() *schema.Resource {
 		Read:   resourcePostgreSQLRoleMembershipRead,
 		Update: resourcePostgreSQLRoleMembershipUpdate,
 		Delete: resourcePostgreSQLRoleMembershipDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
 
 		Schema: map[string]*schema.Schema{
-			"name": {
+			roleMembershipRoleAttr: {
 				Type:        schema.TypeString,
 				Required:    true,
-				Description: "The name of the role membership",
+				ForceNew:    true,
+				Description: "The name of the group role members belong to",
 			},
-			"role": {
-				Type:        schema.TypeString,
+			roleMembershipMembersAttr: {
+				Type:        schema.TypeSet,
 				Required:    true,
-				Description: "The name of the group role members belong to",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "The list of roles granted membership in the role",
 			},
-			"members": {
-				Type:     schema.TypeSet,
-				Required: true,
-				Elem:     &schema.Schema{Type: schema.TypeString},
-				Set:      schema.HashString,
+			roleMembershipWithAdminOptionAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Permit members to grant the role to others and to revoke it",
 			},
 		},
 	}
@@ -42,13 +54,16 @@ func resourcePostgreSQLRoleMembershipCreate(d *schema.ResourceData, meta interfa
 	c.catalogLock.Lock()
 	defer c.catalogLock.Unlock()
 
-	role := d.Get("role").(string)
-	membersList := expandStringList(d.Get("members").(*schema.Set).List())
-	if err := addMembersToRole(c, membersList, role); err != nil {
+	role := d.Get(roleMembershipRoleAttr).(string)
+	membersList := expandStringList(d.Get(roleMembershipMembersAttr).(*schema.Set).List())
+	withAdminOption := d.Get(roleMembershipWithAdminOptionAttr).(bool)
+
+	if err := addMembersToRole(c, membersList, role, withAdminOption); err != nil {
 		return err
 	}
-	d.SetId(d.Get("name").(string))
-	return resourcePostgreSQLRoleMembershipRead(d, meta)
+
+	d.SetId(role)
+	return resourcePostgreSQLRoleMembershipReadImpl(d, meta)
 }
 
 func resourcePostgreSQLRoleMembershipDelete(d *schema.ResourceData, meta interface{}) error {
@@ -56,50 +71,69 @@ func resourcePostgreSQLRoleMembershipDelete(d *schema.ResourceData, meta interfa
 	c.catalogLock.Lock()
 	defer c.catalogLock.Unlock()
 
-	role := d.Get("role").(string)
-	membersList := expandStringList(d.Get("members").(*schema.Set).List())
+	role := d.Get(roleMembershipRoleAttr).(string)
+	membersList := expandStringList(d.Get(roleMembershipMembersAttr).(*schema.Set).List())
 	if err := removeMembersFromRole(c, membersList, role); err != nil {
 		return err
 	}
 
+	d.SetId("")
 	return nil
 }
 
 func resourcePostgreSQLRoleMembershipRead(d *schema.ResourceData, meta interface{}) error {
-	return resourcePostgreSQLRoleReadImpl(d, meta)
+	c := meta.(*Client)
+	c.catalogLock.RLock()
+	defer c.catalogLock.RUnlock()
+
+	return resourcePostgreSQLRoleMembershipReadImpl(d, meta)
 }
 
 func resourcePostgreSQLRoleMembershipReadImpl(d *schema.ResourceData, meta interface{}) error {
 	c := meta.(*Client)
-	role := d.Get("role").(string)
-	var members []string
 
-	roleSQL := fmt.Sprintf("SELECT rolname FROM pg_roles u JOIN pg_group g ON u.oid = ANY(g.grolist) WHERE g.groname=$1;")
-	rows, err := c.DB().Query(roleSQL, role)
+	role := d.Id()
+
+	query := `
+SELECT m.rolname, am.admin_option
+FROM pg_auth_members am
+JOIN pg_roles r ON r.oid = am.roleid
+JOIN pg_roles m ON m.oid = am.member
+WHERE r.rolname = $1
+`
+	rows, err := c.DB().Query(query, role)
 	if err != nil {
-		log.Fatal(err)
+		return errwrap.Wrapf(fmt.Sprintf("Error reading membership for role %s: {{err}}", role), err)
 	}
 	defer rows.Close()
+
+	var members []string
+	withAdminOption := false
 	for rows.Next() {
 		var member string
-		if err := rows.Scan(&member); err != nil {
-			log.Fatal(err)
+		var adminOption bool
+		if err := rows.Scan(&member, &adminOption); err != nil {
+			return errwrap.Wrapf(fmt.Sprintf("Error scanning membership for role %s: {{err}}", role), err)
 		}
 		members = append(members, member)
+		if adminOption {
+			withAdminOption = true
+		}
 	}
-	err = rows.Err()
-	switch {
-	case err == sql.ErrNoRows:
-		log.Printf("[WARN] PostgreSQL roles belonging to (%s) not found", role)
+	if err := rows.Err(); err != nil {
+		return errwrap.Wrapf(fmt.Sprintf("Error reading membership for role %s: {{err}}", role), err)
+	}
+
+	if len(members) == 0 {
+		log.Printf("[WARN] PostgreSQL roles belonging to (%s) not found, removing from state", role)
 		d.SetId("")
 		return nil
-	case err != nil:
-		return errwrap.Wrapf("Error reading ROLE: {{err}}", err)
 	}
 
-	d.Set("role", role)
-	if err := d.Set("members", members); err != nil {
-		return fmt.Errorf("[WARN] Error setting role memberss from PostgreSQL role (%s), error: %s", role, err)
+	d.Set(roleMembershipRoleAttr, role)
+	d.Set(roleMembershipWithAdminOptionAttr, withAdminOption)
+	if err := d.Set(roleMembershipMembersAttr, members); err != nil {
+		return fmt.Errorf("Error setting role members for PostgreSQL role (%s): %w", role, err)
 	}
 
 	return nil
@@ -110,10 +144,11 @@ func resourcePostgreSQLRoleMembershipUpdate(d *schema.ResourceData, meta interfa
 	c.catalogLock.Lock()
 	defer c.catalogLock.Unlock()
 
-	if d.HasChange("members") {
-		role := d.Get("role").(string)
+	role := d.Get(roleMembershipRoleAttr).(string)
+	withAdminOption := d.Get(roleMembershipWithAdminOptionAttr).(bool)
 
-		o, n := d.GetChange("members")
+	if d.HasChange(roleMembershipMembersAttr) {
+		o, n := d.GetChange(roleMembershipMembersAttr)
 		if o == nil {
 			o = new(schema.Set)
 		}
@@ -130,30 +165,78 @@ func resourcePostgreSQLRoleMembershipUpdate(d *schema.ResourceData, meta interfa
 			return err
 		}
 
-		if err := addMembersToRole(c, add, role); err != nil {
+		if err := addMembersToRole(c, add, role, withAdminOption); err != nil {
+			return err
+		}
+	} else if d.HasChange(roleMembershipWithAdminOptionAttr) {
+		// WITH ADMIN OPTION changed but membership didn't: re-grant every
+		// existing member so the admin_option flag on pg_auth_members is updated.
+		members := expandStringList(d.Get(roleMembershipMembersAttr).(*schema.Set).List())
+		if err := addMembersToRole(c, members, role, withAdminOption); err != nil {
 			return err
 		}
 	}
 
-	return resourcePostgreSQLRoleReadImpl(d, meta)
+	return resourcePostgreSQLRoleMembershipReadImpl(d, meta)
 }
 
 func removeMembersFromRole(c *Client, membersList []string, role string) error {
+	if len(membersList) == 0 {
+		return nil
+	}
+
+	txn, err := c.DB().BeginTx(context.Background(), nil)
+	if err != nil {
+		return errwrap.Wrapf("Error starting transaction: {{err}}", err)
+	}
+	defer txn.Rollback()
+
 	for _, member := range membersList {
-		sql := fmt.Sprintf("REVOKE \"%s\" FROM \"%s\"", role, member)
-		if _, err := c.DB().Exec(sql); err != nil {
+		sql := fmt.Sprintf(
+			"REVOKE %s FROM %s",
+			quoteIdentifier(role),
+			quoteIdentifier(member),
+		)
+		if _, err := txn.Exec(sql); err != nil {
 			return errwrap.Wrapf(fmt.Sprintf("Error revoking %s from %s: {{err}}", role, member), err)
 		}
 	}
+
+	if err := txn.Commit(); err != nil {
+		return errwrap.Wrapf("Error committing role membership revoke: {{err}}", err)
+	}
+
 	return nil
 }
 
-func addMembersToRole(c *Client, membersList []string, role string) error {
+func addMembersToRole(c *Client, membersList []string, role string, withAdminOption bool) error {
+	if len(membersList) == 0 {
+		return nil
+	}
+
+	txn, err := c.DB().BeginTx(context.Background(), nil)
+	if err != nil {
+		return errwrap.Wrapf("Error starting transaction: {{err}}", err)
+	}
+	defer txn.Rollback()
+
 	for _, member := range membersList {
-		sql := fmt.Sprintf("GRANT \"%s\" TO \"%s\"", role, member)
-		if _, err := c.DB().Exec(sql); err != nil {
+		sql := fmt.Sprintf(
+			"GRANT %s TO %s",
+			quoteIdentifier(role),
+			quoteIdentifier(member),
+		)
+		if withAdminOption {
+			sql += " WITH ADMIN OPTION"
+		}
+		if _, err := txn.Exec(sql); err != nil {
 			return errwrap.Wrapf(fmt.Sprintf("Error granting %s to role %s: {{err}}", role, member), err)
 		}
 	}
+
+	if err := txn.Commit(); err != nil {
+		return errwrap.Wrapf("Error committing role membership grant: {{err}}", err)
+	}
+
 	return nil
 }
@@ -6,18 +6,18 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/hashicorp/terraform/helper/acctest"
-	"github.com/hashicorp/terraform/helper/resource"
-	"github.com/hashicorp/terraform/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 )
 
 func TestAccPostgresqlRoleMembership_Basic(t *testing.T) {
 	var role string
 
 	rString := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
-	configBase := fmt.Sprintf(testAccPostgreSQLRoleMemberConfig, rString, rString, rString)
-	configUpdate := fmt.Sprintf(testAccPostgreSQLRoleMemberConfigUpdate, rString, rString, rString, rString, rString)
-	configUpdateDown := fmt.Sprintf(testAccPostgreSQLRoleMemberConfigUpdateDown, rString, rString, rString)
+	configBase := fmt.Sprintf(testAccPostgreSQLRoleMemberConfig, rString, rString)
+	configUpdate := fmt.Sprintf(testAccPostgreSQLRoleMemberConfigUpdate, rString, rString, rString, rString)
+	configUpdateDown := fmt.Sprintf(testAccPostgreSQLRoleMemberConfigUpdateDown, rString, rString)
 
 	testMember := fmt.Sprintf("test-member-%s", rString)
 	testMemberTwo := fmt.Sprintf("test-member-two-%s", rString)
@@ -102,7 +102,13 @@ func testAccCheckPostgreSQLRoleMembershipAttributes(role *string, members []stri
 		}
 		c := testAccProvider.Meta().(*Client)
 		var actual_members []string
-		roleSQL := fmt.Sprintf("SELECT rolname FROM pg_roles u JOIN pg_group g ON u.oid = ANY(g.grolist) WHERE g.groname=$1;")
+		roleSQL := `
+SELECT m.rolname
+FROM pg_auth_members am
+JOIN pg_roles r ON r.oid = am.roleid
+JOIN pg_roles m ON m.oid = am.member
+WHERE r.rolname = $1
+`
 		rows, err := c.DB().Query(roleSQL, role)
 		if err != nil {
 			fmt.Errorf("Error reading info about role: %s", err)
@@ -158,9 +164,9 @@ resource "postgresql_role" "member" {
     name = "test-member-%s"
 }
 resource "postgresql_role_membership" "membership" {
-    name = "tf-testing-role-membership-%s"
     members = ["${postgresql_role.member.name}"]
     role = "${postgresql_role.role.name}"
+    with_admin_option = true
 }
 `
 
@@ -178,7 +184,6 @@ resource "postgresql_role" "member_three" {
     name = "test-member-three-%s"
 }
 resource "postgresql_role_membership" "membership" {
-    name = "tf-testing-role-membership-%s"
     members = [
         "${postgresql_role.member_two.name}",
         "${postgresql_role.member_three.name}",
@@ -195,7 +200,6 @@ resource "postgresql_role" "member_three" {
     name = "test-member-three-%s"
 }
 resource "postgresql_role_membership" "membership" {
-    name = "tf-testing-group-membership-%s"
     members = [
         "${postgresql_role.member_three.name}",
     ]
@@ -11,23 +11,33 @@ import (
 
 	"github.com/hashicorp/errwrap"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
-	"github.com/lib/pq"
 	acl "github.com/sean-/postgresql-acl"
 )
 
 const (
-	schemaNameAttr     = "name"
-	schemaDatabaseAttr = "database"
-	schemaOwnerAttr    = "owner"
-	schemaPolicyAttr   = "policy"
-	schemaIfNotExists  = "if_not_exists"
-	schemaDropCascade  = "drop_cascade"
+	schemaNameAttr       = "name"
+	schemaDatabaseAttr   = "database"
+	schemaOwnerAttr      = "owner"
+	schemaPolicyAttr     = "policy"
+	schemaIfNotExists    = "if_not_exists"
+	schemaDropCascade    = "drop_cascade"
+	schemaCommentAttr    = "comment"
+	schemaSearchPathAttr = "search_path"
 
 	schemaPolicyCreateAttr          = "create"
 	schemaPolicyCreateWithGrantAttr = "create_with_grant"
 	schemaPolicyRoleAttr            = "role"
 	schemaPolicyUsageAttr           = "usage"
 	schemaPolicyUsageWithGrantAttr  = "usage_with_grant"
+
+	schemaPolicyTablePrivilegesAttr    = "table_privileges"
+	schemaPolicySequencePrivilegesAttr = "sequence_privileges"
+	schemaPolicyFunctionPrivilegesAttr = "function_privileges"
+
+	schemaReassignOwnedToAttr       = "reassign_owned_to"
+	schemaDropOwnedBeforeDeleteAttr = "drop_owned_before_delete"
+
+	schemaPolicyAuthoritativeAttr = "authoritative"
 )
 
 func resourcePostgreSQLSchema() *schema.Resource {
@@ -71,6 +81,34 @@ func resourcePostgreSQLSchema() *schema.Resource {
 				Default:     false,
 				Description: "When true, will also drop all the objects that are contained in the schema",
 			},
+			schemaReassignOwnedToAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Before dropping the schema, REASSIGN OWNED BY the schema's owner TO this role. Objects in the schema owned by other roles are not reassigned",
+			},
+			schemaDropOwnedBeforeDeleteAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Before dropping the schema, DROP OWNED BY the schema's owner, to clear any dependent objects that would otherwise make DROP SCHEMA fail even with CASCADE",
+			},
+			schemaCommentAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Comment to set on the schema, managed via COMMENT ON SCHEMA",
+			},
+			schemaSearchPathAttr: {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Schema search_path to set for the owner role in this database, via ALTER ROLE ... IN DATABASE ... SET search_path. Requires owner to be set.",
+			},
+			schemaPolicyAuthoritativeAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "When true, the policy block is treated as authoritative: REVOKE ALL is issued against PUBLIC and any role holding a privilege on the schema that isn't described by a policy block, in addition to applying the configured GRANTs. When false (default), only the roles Terraform previously granted are revoked/re-granted, and out-of-band grants are left alone.",
+			},
 			schemaPolicyAttr: {
 				Type:     schema.TypeSet,
 				Optional: true,
@@ -112,6 +150,27 @@ func resourcePostgreSQLSchema() *schema.Resource {
 							Description:   "If true, allow the specified ROLEs to use objects within the schema(s) and GRANT the same USAGE privilege to different ROLEs",
 							ConflictsWith: []string{schemaPolicyAttr + "." + schemaPolicyUsageAttr},
 						},
+						schemaPolicyTablePrivilegesAttr: {
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Set:         schema.HashString,
+							Description: "Privileges (e.g. SELECT, INSERT, UPDATE, DELETE) to grant ON ALL TABLES IN SCHEMA to the role",
+						},
+						schemaPolicySequencePrivilegesAttr: {
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Set:         schema.HashString,
+							Description: "Privileges (e.g. SELECT, USAGE) to grant ON ALL SEQUENCES IN SCHEMA to the role",
+						},
+						schemaPolicyFunctionPrivilegesAttr: {
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Set:         schema.HashString,
+							Description: "Privileges (e.g. EXECUTE) to grant ON ALL FUNCTIONS IN SCHEMA to the role",
+						},
 					},
 				},
 			},
@@ -149,10 +208,10 @@ func resourcePostgreSQLSchemaCreate(d *schema.ResourceData, meta interface{}) er
 				fmt.Fprint(b, "IF NOT EXISTS ")
 			}
 		}
-		fmt.Fprint(b, pq.QuoteIdentifier(schemaName))
+		fmt.Fprint(b, quoteIdentifier(schemaName))
 
 		if schemaOwner != "" {
-			fmt.Fprint(b, " AUTHORIZATION ", pq.QuoteIdentifier(schemaOwner))
+			fmt.Fprint(b, " AUTHORIZATION ", quoteIdentifier(schemaOwner))
 		}
 		queries = append(queries, b.String())
 	} else {
@@ -162,33 +221,24 @@ func resourcePostgreSQLSchemaCreate(d *schema.ResourceData, meta interface{}) er
 	}
 
 	// ACL objects that can generate the necessary SQL
-	type RoleKey string
-	var schemaPolicies map[RoleKey]acl.Schema
-
-	if policiesRaw, ok := d.GetOk(schemaPolicyAttr); ok {
-		policiesList := policiesRaw.(*schema.Set).List()
-
-		// NOTE: len(policiesList) doesn't take into account multiple
-		// roles per policy.
-		schemaPolicies = make(map[RoleKey]acl.Schema, len(policiesList))
+	desiredPolicies, desiredPolicyMaps := computeDesiredSchemaPolicies(d)
 
-		for _, policyRaw := range policiesList {
-			policyMap := policyRaw.(map[string]interface{})
-			rolePolicy := schemaPolicyToACL(policyMap)
-
-			roleKey := RoleKey(strings.ToLower(rolePolicy.Role))
-			if existingRolePolicy, ok := schemaPolicies[roleKey]; ok {
-				schemaPolicies[roleKey] = existingRolePolicy.Merge(rolePolicy)
-			} else {
-				schemaPolicies[roleKey] = rolePolicy
-			}
+	if d.Get(schemaPolicyAuthoritativeAttr).(bool) {
+		revokeQueries, err := authoritativeRevokeQueries(txn, schemaName, desiredPolicies)
+		if err != nil {
+			return err
 		}
+		queries = append(queries, revokeQueries...)
 	}
 
-	for _, policy := range schemaPolicies {
+	for _, policy := range desiredPolicies {
 		queries = append(queries, policy.Grants(schemaName)...)
 	}
 
+	for _, policyMap := range desiredPolicyMaps {
+		queries = append(queries, schemaObjectClassGrants(policyMap, schemaName)...)
+	}
+
 	// Needed in order to set the owner of the schema if the connection user is not a
 	// superuser
 	currentUser := c.config.getDatabaseUsername()
@@ -206,6 +256,14 @@ func resourcePostgreSQLSchemaCreate(d *schema.ResourceData, meta interface{}) er
 		}
 	}
 
+	if err := setSchemaComment(txn, d); err != nil {
+		return err
+	}
+
+	if err := setSchemaSearchPath(txn, d, database); err != nil {
+		return err
+	}
+
 	// Revoke the owner privileges if we had to grant it.
 	if ownerGranted {
 		err = revokeRoleMembership(txn, schemaOwner, currentUser)
@@ -256,7 +314,26 @@ func resourcePostgreSQLSchemaDelete(d *schema.ResourceData, meta interface{}) er
 		}
 	}
 
-	sql := fmt.Sprintf("DROP SCHEMA %s %s", pq.QuoteIdentifier(schemaName), dropMode)
+	// Optionally clear out objects owned by schemaOwner before dropping the
+	// schema itself, so that DROP SCHEMA (even CASCADE) doesn't fail on
+	// objects the owner holds outside of the schema (e.g. privileges granted
+	// on objects in other schemas).
+	reassignOwnedTo := d.Get(schemaReassignOwnedToAttr).(string)
+	dropOwnedBeforeDelete := d.Get(schemaDropOwnedBeforeDeleteAttr).(bool)
+	if schemaOwner != "" && reassignOwnedTo != "" {
+		sql := fmt.Sprintf("REASSIGN OWNED BY %s TO %s", quoteIdentifier(schemaOwner), quoteIdentifier(reassignOwnedTo))
+		if _, err = txn.Exec(sql); err != nil {
+			return errwrap.Wrapf(fmt.Sprintf("Error reassigning objects owned by %s: {{err}}", schemaOwner), err)
+		}
+	}
+	if schemaOwner != "" && dropOwnedBeforeDelete {
+		sql := fmt.Sprintf("DROP OWNED BY %s", quoteIdentifier(schemaOwner))
+		if _, err = txn.Exec(sql); err != nil {
+			return errwrap.Wrapf(fmt.Sprintf("Error dropping objects owned by %s: {{err}}", schemaOwner), err)
+		}
+	}
+
+	sql := fmt.Sprintf("DROP SCHEMA %s %s", quoteIdentifier(schemaName), dropMode)
 	if _, err = txn.Exec(sql); err != nil {
 		return errwrap.Wrapf("Error deleting schema: {{err}}", err)
 	}
@@ -333,8 +410,13 @@ func resourcePostgreSQLSchemaReadImpl(d *schema.ResourceData, c *Client) error {
 	defer deferredRollback(txn)
 
 	var schemaOwner string
+	var schemaComment sql.NullString
 	var schemaACLs []string
-	err = txn.QueryRow("SELECT pg_catalog.pg_get_userbyid(n.nspowner), COALESCE(n.nspacl, '{}'::aclitem[])::TEXT[] FROM pg_catalog.pg_namespace n WHERE n.nspname=$1", schemaName).Scan(&schemaOwner, pq.Array(&schemaACLs))
+	err = txn.QueryRow(
+		`SELECT pg_catalog.pg_get_userbyid(n.nspowner), pg_catalog.obj_description(n.oid, 'pg_namespace'), COALESCE(n.nspacl, '{}'::aclitem[])::TEXT[]
+		 FROM pg_catalog.pg_namespace n WHERE n.nspname=$1`,
+		schemaName,
+	).Scan(&schemaOwner, &schemaComment, &schemaACLs)
 	switch {
 	case err == sql.ErrNoRows:
 		log.Printf("[WARN] PostgreSQL schema (%s) not found in database %s", schemaName, database)
@@ -369,12 +451,68 @@ func resourcePostgreSQLSchemaReadImpl(d *schema.ResourceData, c *Client) error {
 		d.Set(schemaNameAttr, schemaName)
 		d.Set(schemaOwnerAttr, schemaOwner)
 		d.Set(schemaDatabaseAttr, database)
+		d.Set(schemaCommentAttr, schemaComment.String)
+
+		searchPath, err := readSchemaOwnerSearchPath(txn, schemaOwner, database)
+		if err != nil {
+			return err
+		}
+		d.Set(schemaSearchPathAttr, searchPath)
+
+		// In authoritative mode, the live ACL is the source of truth: any
+		// role present in nspacl but absent from config is drift, so plan
+		// against what's actually on the schema rather than only against
+		// what Terraform granted last time.
+		if d.Get(schemaPolicyAuthoritativeAttr).(bool) {
+			policies := make([]interface{}, 0, len(schemaPolicies))
+			for _, policy := range schemaPolicies {
+				policy := policy
+				policies = append(policies, schemaPolicyToHCL(&policy))
+			}
+			d.Set(schemaPolicyAttr, policies)
+		}
+
 		d.SetId(generateSchemaID(d, c))
 
 		return nil
 	}
 }
 
+// readSchemaOwnerSearchPath looks up the schema owner's database-scoped
+// search_path override, if any, from pg_db_role_setting. It mirrors
+// parseDefaultRoleFromConfig's approach of scanning a rolconfig array for a
+// `key=value` entry, here restricted to the current database rather than the
+// role-wide (setdatabase = 0) settings.
+func readSchemaOwnerSearchPath(txn *sql.Tx, schemaOwner, database string) ([]string, error) {
+	if schemaOwner == "" {
+		return nil, nil
+	}
+
+	var rolconfig []string
+	err := txn.QueryRow(`
+		SELECT COALESCE(rs.setconfig, ARRAY[]::text[])
+		FROM pg_catalog.pg_roles r
+		LEFT JOIN pg_catalog.pg_db_role_setting rs ON rs.setrole = r.oid AND rs.setdatabase = (SELECT oid FROM pg_catalog.pg_database WHERE datname = $2)
+		WHERE r.rolname = $1`,
+		schemaOwner, database,
+	).Scan(&rolconfig)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, nil
+	case err != nil:
+		return nil, errwrap.Wrapf("Error reading schema owner search_path: {{err}}", err)
+	}
+
+	for _, entry := range rolconfig {
+		if strings.HasPrefix(entry, "search_path=") {
+			value := strings.TrimPrefix(entry, "search_path=")
+			return strings.Split(value, ","), nil
+		}
+	}
+
+	return nil, nil
+}
+
 func resourcePostgreSQLSchemaUpdate(d *schema.ResourceData, meta interface{}) error {
 	c := meta.(*Client)
 
@@ -414,6 +552,14 @@ func resourcePostgreSQLSchemaUpdate(d *schema.ResourceData, meta interface{}) er
 		return err
 	}
 
+	if err := setSchemaComment(txn, d); err != nil {
+		return err
+	}
+
+	if err := setSchemaSearchPath(txn, d, database); err != nil {
+		return err
+	}
+
 	// Revoke the owner privileges if we had to grant it.
 	if ownerGranted {
 		err = revokeRoleMembership(txn, schemaOwner, currentUser)
@@ -441,7 +587,7 @@ func setSchemaName(txn *sql.Tx, d *schema.ResourceData, c *Client) error {
 		return errors.New("Error setting schema name to an empty string")
 	}
 
-	sql := fmt.Sprintf("ALTER SCHEMA %s RENAME TO %s", pq.QuoteIdentifier(o), pq.QuoteIdentifier(n))
+	sql := fmt.Sprintf("ALTER SCHEMA %s RENAME TO %s", quoteIdentifier(o), quoteIdentifier(n))
 	if _, err := txn.Exec(sql); err != nil {
 		return errwrap.Wrapf("Error updating schema NAME: {{err}}", err)
 	}
@@ -462,7 +608,7 @@ func setSchemaOwner(txn *sql.Tx, d *schema.ResourceData) error {
 		return errors.New("Error setting schema owner to an empty string")
 	}
 
-	sql := fmt.Sprintf("ALTER SCHEMA %s OWNER TO %s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(schemaOwner))
+	sql := fmt.Sprintf("ALTER SCHEMA %s OWNER TO %s", quoteIdentifier(schemaName), quoteIdentifier(schemaOwner))
 	if _, err := txn.Exec(sql); err != nil {
 		return errwrap.Wrapf("Error updating schema OWNER: {{err}}", err)
 	}
@@ -470,13 +616,76 @@ func setSchemaOwner(txn *sql.Tx, d *schema.ResourceData) error {
 	return nil
 }
 
+func setSchemaComment(txn *sql.Tx, d *schema.ResourceData) error {
+	if !d.HasChange(schemaCommentAttr) {
+		return nil
+	}
+
+	schemaName := d.Get(schemaNameAttr).(string)
+	comment := d.Get(schemaCommentAttr).(string)
+
+	var sql string
+	if comment == "" {
+		sql = fmt.Sprintf("COMMENT ON SCHEMA %s IS NULL", quoteIdentifier(schemaName))
+	} else {
+		sql = fmt.Sprintf("COMMENT ON SCHEMA %s IS %s", quoteIdentifier(schemaName), quoteLiteral(comment))
+	}
+
+	if _, err := txn.Exec(sql); err != nil {
+		return errwrap.Wrapf("Error updating schema COMMENT: {{err}}", err)
+	}
+
+	return nil
+}
+
+// setSchemaSearchPath sets the search_path the schema's owner sees when
+// connected to this database, via ALTER ROLE ... IN DATABASE ... SET
+// search_path. Unlike the schema's own attributes, this is an owner-role
+// setting, so it only applies (and is only meaningful) when an owner is set.
+func setSchemaSearchPath(txn *sql.Tx, d *schema.ResourceData, database string) error {
+	if !d.HasChange(schemaSearchPathAttr) {
+		return nil
+	}
+
+	searchPathRaw := d.Get(schemaSearchPathAttr).([]interface{})
+	if len(searchPathRaw) == 0 {
+		return nil
+	}
+
+	schemaOwner := d.Get(schemaOwnerAttr).(string)
+	if schemaOwner == "" {
+		return errors.New("search_path requires owner to be set")
+	}
+
+	searchPath := make([]string, len(searchPathRaw))
+	for i, v := range searchPathRaw {
+		searchPath[i] = v.(string)
+	}
+
+	sql := fmt.Sprintf(
+		"ALTER ROLE %s IN DATABASE %s SET search_path = %s",
+		quoteIdentifier(schemaOwner),
+		quoteIdentifier(database),
+		quoteLiteral(strings.Join(searchPath, ",")),
+	)
+	if _, err := txn.Exec(sql); err != nil {
+		return errwrap.Wrapf("Error updating schema search_path: {{err}}", err)
+	}
+
+	return nil
+}
+
 func setSchemaPolicy(txn *sql.Tx, d *schema.ResourceData) error {
-	if !d.HasChange(schemaPolicyAttr) {
+	if !d.HasChange(schemaPolicyAttr) && !d.HasChange(schemaPolicyAuthoritativeAttr) {
 		return nil
 	}
 
 	schemaName := d.Get(schemaNameAttr).(string)
 
+	if d.Get(schemaPolicyAuthoritativeAttr).(bool) {
+		return setSchemaPolicyAuthoritative(txn, d, schemaName)
+	}
+
 	oraw, nraw := d.GetChange(schemaPolicyAttr)
 	oldList := oraw.(*schema.Set).List()
 	newList := nraw.(*schema.Set).List()
@@ -500,6 +709,7 @@ func setSchemaPolicy(txn *sql.Tx, d *schema.ResourceData) error {
 				return errwrap.Wrapf("Error reading schema: {{err}}", err)
 			default:
 				queries = append(queries, rolePolicy.Revokes(schemaName)...)
+				queries = append(queries, schemaObjectClassRevokes(pMap, schemaName)...)
 			}
 		}
 	}
@@ -508,6 +718,7 @@ func setSchemaPolicy(txn *sql.Tx, d *schema.ResourceData) error {
 		pMap := p.(map[string]interface{})
 		rolePolicy := schemaPolicyToACL(pMap)
 		queries = append(queries, rolePolicy.Grants(schemaName)...)
+		queries = append(queries, schemaObjectClassGrants(pMap, schemaName)...)
 	}
 
 	for _, p := range updated {
@@ -520,12 +731,14 @@ func setSchemaPolicy(txn *sql.Tx, d *schema.ResourceData) error {
 			oldPolicies := policies[0].(map[string]interface{})
 			rolePolicy := schemaPolicyToACL(oldPolicies)
 			queries = append(queries, rolePolicy.Revokes(schemaName)...)
+			queries = append(queries, schemaObjectClassRevokes(oldPolicies, schemaName)...)
 		}
 
 		{
 			newPolicies := policies[1].(map[string]interface{})
 			rolePolicy := schemaPolicyToACL(newPolicies)
 			queries = append(queries, rolePolicy.Grants(schemaName)...)
+			queries = append(queries, schemaObjectClassGrants(newPolicies, schemaName)...)
 		}
 	}
 
@@ -538,6 +751,123 @@ func setSchemaPolicy(txn *sql.Tx, d *schema.ResourceData) error {
 	return nil
 }
 
+// setSchemaPolicyAuthoritative makes the schema's ACL match the configured
+// policy blocks exactly, rather than only reacting to Terraform's own
+// prior/new state diff the way setSchemaPolicy's additive path does: it
+// REVOKEs from PUBLIC and from every role holding a live privilege on the
+// schema that isn't described by a policy block, then GRANTs the merged
+// desired privileges, all batched into a single multi-statement Exec.
+func setSchemaPolicyAuthoritative(txn *sql.Tx, d *schema.ResourceData, schemaName string) error {
+	desiredPolicies, desiredPolicyMaps := computeDesiredSchemaPolicies(d)
+
+	queries, err := authoritativeRevokeQueries(txn, schemaName, desiredPolicies)
+	if err != nil {
+		return err
+	}
+
+	for _, policy := range desiredPolicies {
+		queries = append(queries, policy.Grants(schemaName)...)
+	}
+
+	for _, policyMap := range desiredPolicyMaps {
+		queries = append(queries, schemaObjectClassGrants(policyMap, schemaName)...)
+	}
+
+	if _, err := txn.Exec(strings.Join(queries, ";\n")); err != nil {
+		return errwrap.Wrapf("Error applying authoritative schema DCL: {{err}}", err)
+	}
+
+	return nil
+}
+
+// computeDesiredSchemaPolicies merges the configured policy blocks into one
+// acl.Schema per role (keyed by lowercased role name, "" for PUBLIC), and
+// also hands back the raw policy maps so object-class grants can be derived.
+func computeDesiredSchemaPolicies(d *schema.ResourceData) (map[string]acl.Schema, []map[string]interface{}) {
+	desired := make(map[string]acl.Schema)
+	var policyMaps []map[string]interface{}
+
+	if policiesRaw, ok := d.GetOk(schemaPolicyAttr); ok {
+		for _, policyRaw := range policiesRaw.(*schema.Set).List() {
+			policyMap := policyRaw.(map[string]interface{})
+			policyMaps = append(policyMaps, policyMap)
+
+			rolePolicy := schemaPolicyToACL(policyMap)
+			roleKey := strings.ToLower(rolePolicy.Role)
+			if existing, ok := desired[roleKey]; ok {
+				desired[roleKey] = existing.Merge(rolePolicy)
+			} else {
+				desired[roleKey] = rolePolicy
+			}
+		}
+	}
+
+	return desired, policyMaps
+}
+
+// authoritativeRevokeQueries returns the REVOKE statements needed to strip
+// every privilege the live nspacl grants that isn't accounted for by
+// desired: an unconditional REVOKE ALL FROM PUBLIC (PUBLIC grants are never
+// desirable side effects of other tooling) plus a REVOKE ALL for each
+// non-PUBLIC role holding a live privilege that has no matching policy
+// block.
+func authoritativeRevokeQueries(txn *sql.Tx, schemaName string, desired map[string]acl.Schema) ([]string, error) {
+	liveRoles, err := schemaLiveACLRoles(txn, schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	queries := []string{fmt.Sprintf("REVOKE ALL ON SCHEMA %s FROM PUBLIC", quoteIdentifier(schemaName))}
+
+	for roleName := range liveRoles {
+		if roleName == "" {
+			continue
+		}
+		if _, ok := desired[roleName]; !ok {
+			queries = append(queries, fmt.Sprintf("REVOKE ALL ON SCHEMA %s FROM %s", quoteIdentifier(schemaName), quoteIdentifier(roleName)))
+		}
+	}
+
+	return queries, nil
+}
+
+// schemaLiveACLRoles returns the lowercased role names (empty string for
+// PUBLIC) that currently hold any privilege on the schema according to
+// nspacl, regardless of whether Terraform granted them. Used by the
+// authoritative policy mode to detect drift introduced outside Terraform.
+func schemaLiveACLRoles(txn *sql.Tx, schemaName string) (map[string]bool, error) {
+	var schemaACLs []string
+	err := txn.QueryRow(
+		"SELECT COALESCE(n.nspacl, '{}'::aclitem[])::TEXT[] FROM pg_catalog.pg_namespace n WHERE n.nspname=$1",
+		schemaName,
+	).Scan(&schemaACLs)
+	if err == sql.ErrNoRows {
+		// The schema is created in the same batch (Create), so nobody holds
+		// a privilege on it yet.
+		return map[string]bool{}, nil
+	}
+	if err != nil {
+		return nil, errwrap.Wrapf("Error reading schema ACL: {{err}}", err)
+	}
+
+	roles := make(map[string]bool, len(schemaACLs))
+	for _, aclStr := range schemaACLs {
+		aclItem, err := acl.Parse(aclStr)
+		if err != nil {
+			return nil, errwrap.Wrapf("Error parsing aclitem: {{err}}", err)
+		}
+
+		schemaACL, err := acl.NewSchema(aclItem)
+		if err != nil {
+			return nil, errwrap.Wrapf("invalid perms for schema: {{err}}", err)
+		}
+
+		roles[strings.ToLower(schemaACL.Role)] = true
+	}
+
+	return roles, nil
+}
+
 // schemaChangedPolicies walks old and new to create a set of queries that can
 // be executed to enact each type of state change (roles that have been dropped
 // from the policy, added to a policy, have updated privilges, or are
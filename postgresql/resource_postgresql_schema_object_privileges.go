@@ -0,0 +1,163 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// schemaObjectClassSpec describes one of the ON ALL <class> IN SCHEMA object
+// classes the policy block can grant across. The pg_class.relkind values (or,
+// for functions, the pg_proc-based query) used to read a class back are
+// picked from sqlNoun by readIntersectedPrivileges.
+type schemaObjectClassSpec struct {
+	attr    string
+	sqlNoun string
+}
+
+var schemaObjectClassSpecs = []schemaObjectClassSpec{
+	{attr: schemaPolicyTablePrivilegesAttr, sqlNoun: "TABLES"},
+	{attr: schemaPolicySequencePrivilegesAttr, sqlNoun: "SEQUENCES"},
+	{attr: schemaPolicyFunctionPrivilegesAttr, sqlNoun: "FUNCTIONS"},
+}
+
+// schemaObjectClassGrants builds the GRANT ... ON ALL <class> IN SCHEMA
+// statements for a single policy role entry's table_privileges /
+// sequence_privileges / function_privileges.
+func schemaObjectClassGrants(policyMap map[string]interface{}, schemaName string) []string {
+	return schemaObjectClassQueries(policyMap, schemaName, "GRANT %s ON ALL %s IN SCHEMA %s TO %s")
+}
+
+// schemaObjectClassRevokes builds the matching REVOKE ALL PRIVILEGES ON ALL
+// <class> IN SCHEMA statements.
+func schemaObjectClassRevokes(policyMap map[string]interface{}, schemaName string) []string {
+	var queries []string
+	role := schemaPolicyRoleOrPublic(policyMap)
+	for _, spec := range schemaObjectClassSpecs {
+		if len(getStringSet(policyMap, spec.attr)) == 0 {
+			continue
+		}
+		queries = append(queries, fmt.Sprintf(
+			"REVOKE ALL PRIVILEGES ON ALL %s IN SCHEMA %s FROM %s",
+			spec.sqlNoun, quoteIdentifier(schemaName), role,
+		))
+	}
+	return queries
+}
+
+func schemaObjectClassQueries(policyMap map[string]interface{}, schemaName, format string) []string {
+	var queries []string
+	role := schemaPolicyRoleOrPublic(policyMap)
+	for _, spec := range schemaObjectClassSpecs {
+		privileges := getStringSet(policyMap, spec.attr)
+		if len(privileges) == 0 {
+			continue
+		}
+		queries = append(queries, fmt.Sprintf(
+			format, strings.Join(privileges, ", "), spec.sqlNoun, quoteIdentifier(schemaName), role,
+		))
+	}
+	return queries
+}
+
+func schemaPolicyRoleOrPublic(policyMap map[string]interface{}) string {
+	role, _ := policyMap[schemaPolicyRoleAttr].(string)
+	if role == "" {
+		return "PUBLIC"
+	}
+	return quoteIdentifier(role)
+}
+
+func getStringSet(m map[string]interface{}, key string) []string {
+	raw, ok := m[key]
+	if !ok {
+		return nil
+	}
+	set, ok := raw.(*schema.Set)
+	if !ok {
+		return nil
+	}
+	out := make([]string, 0, set.Len())
+	for _, v := range set.List() {
+		out = append(out, v.(string))
+	}
+	return out
+}
+
+// readSchemaObjectClassPrivileges reconciles the table_privileges /
+// sequence_privileges / function_privileges granted to roleName in
+// schemaName, by intersecting privileges across every object of the given
+// class: a privilege only counts as "set" when every table (or sequence, or
+// function) in the schema has it, mirroring what GRANT ... ON ALL <class> IN
+// SCHEMA would have produced.
+func readSchemaObjectClassPrivileges(client *Client, database, schemaName, roleName string) (map[string][]string, error) {
+	txn, err := startTransaction(client, database)
+	if err != nil {
+		return nil, err
+	}
+	defer deferredRollback(txn)
+
+	result := make(map[string][]string, len(schemaObjectClassSpecs))
+	for _, spec := range schemaObjectClassSpecs {
+		privileges, err := readIntersectedPrivileges(txn, spec, schemaName, roleName)
+		if err != nil {
+			return nil, err
+		}
+		result[spec.attr] = privileges
+	}
+	return result, nil
+}
+
+const schemaObjectClassPrivilegesQuery = `
+WITH objects AS (
+	SELECT c.oid, c.relacl AS acl
+	FROM pg_class c
+	WHERE c.relnamespace = (SELECT oid FROM pg_namespace WHERE nspname = $1)
+	  AND c.relkind = ANY($2)
+	UNION ALL
+	SELECT p.oid, p.proacl AS acl
+	FROM pg_proc p
+	WHERE p.pronamespace = (SELECT oid FROM pg_namespace WHERE nspname = $1)
+	  AND $3
+), exploded AS (
+	SELECT objects.oid, (aclexplode(coalesce(objects.acl, '{}'))).*
+	FROM objects
+)
+SELECT privilege_type
+FROM exploded
+JOIN pg_roles r ON r.oid = exploded.grantee
+WHERE r.rolname = $4
+GROUP BY privilege_type
+HAVING COUNT(DISTINCT exploded.oid) = (SELECT COUNT(*) FROM objects)
+   AND (SELECT COUNT(*) FROM objects) > 0
+`
+
+func readIntersectedPrivileges(txn *sql.Tx, spec schemaObjectClassSpec, schemaName, roleName string) ([]string, error) {
+	relkinds := []string{}
+	isFunction := spec.sqlNoun == "FUNCTIONS"
+	if !isFunction {
+		if spec.sqlNoun == "TABLES" {
+			relkinds = []string{"r", "p"}
+		} else {
+			relkinds = []string{"S"}
+		}
+	}
+
+	rows, err := txn.Query(schemaObjectClassPrivilegesQuery, schemaName, relkinds, isFunction, roleName)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s privileges for schema %s: %w", strings.ToLower(spec.sqlNoun), schemaName, err)
+	}
+	defer rows.Close()
+
+	var privileges []string
+	for rows.Next() {
+		var p string
+		if err := rows.Scan(&p); err != nil {
+			return nil, fmt.Errorf("could not scan %s privilege: %w", strings.ToLower(spec.sqlNoun), err)
+		}
+		privileges = append(privileges, p)
+	}
+	return privileges, rows.Err()
+}
@@ -0,0 +1,54 @@
+package postgresql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestSchemaObjectClassGrants(t *testing.T) {
+	policyMap := map[string]interface{}{
+		schemaPolicyRoleAttr:            "reader",
+		schemaPolicyTablePrivilegesAttr: schema.NewSet(schema.HashString, []interface{}{"SELECT"}),
+	}
+
+	queries := schemaObjectClassGrants(policyMap, "myschema")
+	if len(queries) != 1 {
+		t.Fatalf("expected 1 query, got %d: %v", len(queries), queries)
+	}
+	want := `GRANT SELECT ON ALL TABLES IN SCHEMA "myschema" TO "reader"`
+	if queries[0] != want {
+		t.Errorf("got %q, want %q", queries[0], want)
+	}
+}
+
+func TestSchemaObjectClassGrants_PublicRole(t *testing.T) {
+	policyMap := map[string]interface{}{
+		schemaPolicySequencePrivilegesAttr: schema.NewSet(schema.HashString, []interface{}{"USAGE", "SELECT"}),
+	}
+
+	queries := schemaObjectClassGrants(policyMap, "myschema")
+	if len(queries) != 1 {
+		t.Fatalf("expected 1 query, got %d: %v", len(queries), queries)
+	}
+	if !strings.Contains(queries[0], "ON ALL SEQUENCES IN SCHEMA") || !strings.Contains(queries[0], "TO PUBLIC") {
+		t.Errorf("unexpected query: %s", queries[0])
+	}
+}
+
+func TestSchemaObjectClassRevokes(t *testing.T) {
+	policyMap := map[string]interface{}{
+		schemaPolicyRoleAttr:               "reader",
+		schemaPolicyFunctionPrivilegesAttr: schema.NewSet(schema.HashString, []interface{}{"EXECUTE"}),
+	}
+
+	queries := schemaObjectClassRevokes(policyMap, "myschema")
+	if len(queries) != 1 {
+		t.Fatalf("expected 1 query, got %d: %v", len(queries), queries)
+	}
+	want := `REVOKE ALL PRIVILEGES ON ALL FUNCTIONS IN SCHEMA "myschema" FROM "reader"`
+	if queries[0] != want {
+		t.Errorf("got %q, want %q", queries[0], want)
+	}
+}
@@ -0,0 +1,343 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+const (
+	subNameAttr              = "name"
+	subDatabaseAttr          = "database"
+	subConninfoAttr          = "conninfo"
+	subPublicationsAttr      = "publications"
+	subSlotNameAttr          = "slot_name"
+	subCreateSlotAttr        = "create_slot"
+	subEnabledAttr           = "enabled"
+	subSynchronousCommitAttr = "synchronous_commit"
+	subCopyDataAttr          = "copy_data"
+)
+
+// resourcePostgreSQLSubscription manages a PostgreSQL 10+ logical
+// replication subscription. Like resourcePostgreSQLPublication, it shares
+// the featureSupported gating and the startTransaction(c,
+// database)/catalogLock conventions resourcePostgreSQLExtension uses.
+func resourcePostgreSQLSubscription() *schema.Resource {
+	return &schema.Resource{
+		Create: resourcePostgreSQLSubscriptionCreate,
+		Read:   resourcePostgreSQLSubscriptionRead,
+		Update: resourcePostgreSQLSubscriptionUpdate,
+		Delete: resourcePostgreSQLSubscriptionDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			subNameAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Name of the subscription",
+			},
+			subDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Computed:    true,
+				Description: "The database the subscription is created in. Defaults to the provider's connection database.",
+			},
+			subConninfoAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				Description: "libpq connection string of the publisher the subscription connects to",
+			},
+			subPublicationsAttr: {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Names of the publications on the publisher to subscribe to",
+			},
+			subSlotNameAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "Name of the replication slot to use. Defaults to the subscription name.",
+			},
+			subCreateSlotAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Whether CREATE SUBSCRIPTION should create the replication slot on the publisher",
+			},
+			subEnabledAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether the subscription should actively replicate (ENABLE/DISABLE)",
+			},
+			subSynchronousCommitAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "synchronous_commit value used by the subscription's apply worker, e.g. `off`",
+			},
+			subCopyDataAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Whether the initial data already present on the publication tables is copied once replication starts. Only meaningful at creation time.",
+			},
+		},
+	}
+}
+
+func resourcePostgreSQLSubscriptionCreate(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*Client)
+
+	if !c.featureSupported(featureLogicalReplication) {
+		return fmt.Errorf(
+			"postgresql_subscription resource is not supported for this Postgres version (%s)",
+			c.version,
+		)
+	}
+
+	c.catalogLock.Lock()
+	defer c.catalogLock.Unlock()
+
+	subName := d.Get(subNameAttr).(string)
+	conninfo := d.Get(subConninfoAttr).(string)
+	publications := stringList(d.Get(subPublicationsAttr).([]interface{}))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "CREATE SUBSCRIPTION %s CONNECTION %s PUBLICATION %s",
+		quoteIdentifier(subName), quoteLiteral(conninfo), strings.Join(quoteIdentifiers(publications), ", "))
+
+	withOptions := subscriptionWithOptions(d, true)
+	if len(withOptions) > 0 {
+		fmt.Fprintf(&b, " WITH (%s)", strings.Join(withOptions, ", "))
+	}
+
+	database := getDatabase(d, c)
+	txn, err := startTransaction(c, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if _, err := txn.Exec(b.String()); err != nil {
+		return fmt.Errorf("Error creating subscription %s: %w", subName, err)
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("Error committing subscription: %w", err)
+	}
+
+	d.SetId(generateSubscriptionID(subName, database))
+
+	return resourcePostgreSQLSubscriptionReadImpl(d, meta)
+}
+
+func resourcePostgreSQLSubscriptionRead(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*Client)
+
+	if !c.featureSupported(featureLogicalReplication) {
+		return fmt.Errorf(
+			"postgresql_subscription resource is not supported for this Postgres version (%s)",
+			c.version,
+		)
+	}
+
+	c.catalogLock.RLock()
+	defer c.catalogLock.RUnlock()
+
+	return resourcePostgreSQLSubscriptionReadImpl(d, meta)
+}
+
+func resourcePostgreSQLSubscriptionReadImpl(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*Client)
+	database := getDatabase(d, c)
+
+	txn, err := startTransaction(c, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	subName := d.Get(subNameAttr).(string)
+
+	var conninfo, slotName, syncCommit string
+	var enabled bool
+	var publications []string
+	query := `
+		SELECT s.subconninfo, s.subenabled, COALESCE(s.subslotname, ''), s.subsynccommit, s.subpublications
+		FROM pg_catalog.pg_subscription s
+		JOIN pg_catalog.pg_database d ON d.oid = s.subdbid
+		WHERE s.subname = $1 AND d.datname = current_database()
+	`
+	err = txn.QueryRow(query, subName).Scan(&conninfo, &enabled, &slotName, &syncCommit, &publications)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] PostgreSQL subscription (%s) not found", subName)
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading subscription %s: %w", subName, err)
+	}
+
+	d.Set(subNameAttr, subName)
+	d.Set(subDatabaseAttr, database)
+	d.Set(subConninfoAttr, conninfo)
+	d.Set(subPublicationsAttr, publications)
+	d.Set(subSlotNameAttr, slotName)
+	d.Set(subEnabledAttr, enabled)
+	d.Set(subSynchronousCommitAttr, syncCommit)
+	d.SetId(generateSubscriptionID(subName, database))
+
+	return nil
+}
+
+func resourcePostgreSQLSubscriptionUpdate(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*Client)
+
+	if !c.featureSupported(featureLogicalReplication) {
+		return fmt.Errorf(
+			"postgresql_subscription resource is not supported for this Postgres version (%s)",
+			c.version,
+		)
+	}
+
+	c.catalogLock.Lock()
+	defer c.catalogLock.Unlock()
+
+	database := getDatabase(d, c)
+	txn, err := startTransaction(c, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	subName := d.Get(subNameAttr).(string)
+
+	if d.HasChange(subConninfoAttr) {
+		sql := fmt.Sprintf("ALTER SUBSCRIPTION %s CONNECTION %s", quoteIdentifier(subName), quoteLiteral(d.Get(subConninfoAttr).(string)))
+		if _, err := txn.Exec(sql); err != nil {
+			return fmt.Errorf("Error updating subscription %s connection: %w", subName, err)
+		}
+	}
+
+	if d.HasChange(subPublicationsAttr) {
+		publications := stringList(d.Get(subPublicationsAttr).([]interface{}))
+		sql := fmt.Sprintf("ALTER SUBSCRIPTION %s SET PUBLICATION %s", quoteIdentifier(subName), strings.Join(quoteIdentifiers(publications), ", "))
+		if _, err := txn.Exec(sql); err != nil {
+			return fmt.Errorf("Error updating subscription %s publications: %w", subName, err)
+		}
+	}
+
+	if d.HasChange(subSynchronousCommitAttr) {
+		withOptions := subscriptionWithOptions(d, false)
+		if len(withOptions) > 0 {
+			sql := fmt.Sprintf("ALTER SUBSCRIPTION %s SET (%s)", quoteIdentifier(subName), strings.Join(withOptions, ", "))
+			if _, err := txn.Exec(sql); err != nil {
+				return fmt.Errorf("Error updating subscription %s options: %w", subName, err)
+			}
+		}
+	}
+
+	if d.HasChange(subEnabledAttr) {
+		verb := "DISABLE"
+		if d.Get(subEnabledAttr).(bool) {
+			verb = "ENABLE"
+		}
+		sql := fmt.Sprintf("ALTER SUBSCRIPTION %s %s", quoteIdentifier(subName), verb)
+		if _, err := txn.Exec(sql); err != nil {
+			return fmt.Errorf("Error updating subscription %s enabled state: %w", subName, err)
+		}
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("Error committing subscription update: %w", err)
+	}
+
+	return resourcePostgreSQLSubscriptionReadImpl(d, meta)
+}
+
+func resourcePostgreSQLSubscriptionDelete(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*Client)
+
+	if !c.featureSupported(featureLogicalReplication) {
+		return fmt.Errorf(
+			"postgresql_subscription resource is not supported for this Postgres version (%s)",
+			c.version,
+		)
+	}
+
+	c.catalogLock.Lock()
+	defer c.catalogLock.Unlock()
+
+	subName := d.Get(subNameAttr).(string)
+	database := getDatabase(d, c)
+
+	txn, err := startTransaction(c, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if _, err := txn.Exec(fmt.Sprintf("DROP SUBSCRIPTION %s", quoteIdentifier(subName))); err != nil {
+		return fmt.Errorf("Error deleting subscription %s: %w", subName, err)
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("Error committing subscription delete: %w", err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// subscriptionWithOptions renders the WITH (...) option list shared by
+// CREATE SUBSCRIPTION and ALTER SUBSCRIPTION ... SET. create-only options
+// (slot_name, create_slot, copy_data) are only included when forCreate is
+// true, since Postgres rejects them on ALTER SUBSCRIPTION ... SET.
+func subscriptionWithOptions(d *schema.ResourceData, forCreate bool) []string {
+	var options []string
+
+	if forCreate {
+		if v, ok := d.GetOk(subSlotNameAttr); ok {
+			options = append(options, fmt.Sprintf("slot_name = %s", quoteLiteral(v.(string))))
+		}
+		options = append(options, fmt.Sprintf("create_slot = %t", d.Get(subCreateSlotAttr).(bool)))
+		options = append(options, fmt.Sprintf("copy_data = %t", d.Get(subCopyDataAttr).(bool)))
+		options = append(options, fmt.Sprintf("enabled = %t", d.Get(subEnabledAttr).(bool)))
+	}
+
+	if v, ok := d.GetOk(subSynchronousCommitAttr); ok {
+		options = append(options, fmt.Sprintf("synchronous_commit = %s", quoteLiteral(v.(string))))
+	}
+
+	sort.Strings(options)
+
+	return options
+}
+
+func quoteIdentifiers(names []string) []string {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = quoteIdentifier(n)
+	}
+	return quoted
+}
+
+func generateSubscriptionID(name, database string) string {
+	return strings.Join([]string{database, name}, ".")
+}
@@ -1,23 +1,37 @@
 package postgresql
 
 import (
-	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"testing"
 )
 
 func TestAccPostgresqlSsh_Connect(t *testing.T) {
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheckSsh(t) },
-		Providers: testAccProviders,
+		PreCheck:     func() { testAccPreCheckSsh(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlDatabaseDestroy,
 		Steps: []resource.TestStep{
 			{
-				Config: "",
-				Check:  resource.ComposeTestCheckFunc(),
+				Config: testAccPostgreSQLSshTunnelConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlDatabaseExists("postgresql_database.ssh_tunnel"),
+				),
 			},
 		},
 	})
 }
 
+const testAccPostgreSQLSshTunnelConfig = `
+provider "postgresql" {
+  alias = "postgresql+ssh"
+}
+
+resource "postgresql_database" "ssh_tunnel" {
+  provider = postgresql.postgresql+ssh
+  name     = "tf_tests_ssh_tunnel_db"
+}
+`
+
 func TestAccPostgresqlSshDatabase_Basic(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheckSsh(t) },
@@ -0,0 +1,296 @@
+package postgresql
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/errwrap"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// sshTunnel opens a local TCP listener that forwards connections through an
+// SSH bastion host to the real PostgreSQL server. It is owned by the Client,
+// shared across every connection the pgx pool opens (see Acquire/Release),
+// and torn down by Close when the Client itself is closed.
+type sshTunnel struct {
+	client *ssh.Client
+
+	localListener net.Listener
+	remoteAddr    string
+
+	mu       sync.Mutex
+	started  bool
+	stopCh   chan struct{}
+	stopOnce sync.Once
+
+	// refCount tracks how many pooled connections are currently relying on
+	// the tunnel. It does not drive teardown — pgx pool connections come and
+	// go constantly, and closing the bastion session every time they drain
+	// to zero would mean redialing it on the very next query — it only lets
+	// Close (see below) tell whether it ran while acquirers were still
+	// outstanding. See Acquire/Release.
+	refCount int
+}
+
+// newSshTunnel builds a tunnel from the Ssh* fields on Config and dials the
+// bastion host right away; only the local listener is started lazily, by the
+// first Acquire.
+func newSshTunnel(config Config, remoteHost string, remotePort int) (*sshTunnel, error) {
+	authMethods, err := sshAuthMethods(config)
+	if err != nil {
+		return nil, err
+	}
+
+	hostKeyCallback, err := sshHostKeyCallback(config)
+	if err != nil {
+		return nil, err
+	}
+
+	sshClientConfig := &ssh.ClientConfig{
+		User:            config.SshUser,
+		Auth:            authMethods,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         defaultSshTimeout,
+	}
+
+	bastionAddr := net.JoinHostPort(config.SshHost, strconv.Itoa(config.SshPort))
+	sshClient, err := ssh.Dial("tcp", bastionAddr, sshClientConfig)
+	if err != nil {
+		return nil, errwrap.Wrapf(fmt.Sprintf("Error dialing bastion host %s: {{err}}", bastionAddr), err)
+	}
+
+	return &sshTunnel{
+		client:     sshClient,
+		remoteAddr: net.JoinHostPort(remoteHost, strconv.Itoa(remotePort)),
+		stopCh:     make(chan struct{}),
+	}, nil
+}
+
+// Start opens a local listener and begins forwarding every accepted
+// connection to the remote Postgres host through the SSH client. It returns
+// the local address that callers should rewrite Config.Host/Port to. It is
+// safe to call Start multiple times; only the first call does any work.
+func (t *sshTunnel) Start() (string, int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.started {
+		host, portStr, err := net.SplitHostPort(t.localListener.Addr().String())
+		if err != nil {
+			return "", 0, err
+		}
+		port, _ := strconv.Atoi(portStr)
+		return host, port, nil
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", 0, errwrap.Wrapf("Error starting local tunnel listener: {{err}}", err)
+	}
+	t.localListener = listener
+	t.started = true
+
+	go t.acceptLoop()
+
+	host, portStr, err := net.SplitHostPort(listener.Addr().String())
+	if err != nil {
+		return "", 0, err
+	}
+	port, _ := strconv.Atoi(portStr)
+	return host, port, nil
+}
+
+// Acquire increments the tunnel's reference count and starts it if this is
+// the first acquirer, returning the local address the caller should dial
+// instead of the real Postgres host/port. Every successful Acquire must be
+// paired with a Release. Client.Connect calls this once per pooled
+// connection it opens through the tunnel.
+func (t *sshTunnel) Acquire() (string, int, error) {
+	t.mu.Lock()
+	t.refCount++
+	t.mu.Unlock()
+
+	host, port, err := t.Start()
+	if err != nil {
+		t.mu.Lock()
+		t.refCount--
+		t.mu.Unlock()
+		return "", 0, err
+	}
+
+	return host, port, nil
+}
+
+// Release decrements the tunnel's reference count. It does not close the
+// tunnel itself — see the refCount field doc for why — so it never errors;
+// actual teardown happens in Close, once, at Client.Close time.
+func (t *sshTunnel) Release() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.refCount > 0 {
+		t.refCount--
+	}
+}
+
+func (t *sshTunnel) acceptLoop() {
+	for {
+		localConn, err := t.localListener.Accept()
+		if err != nil {
+			select {
+			case <-t.stopCh:
+				return
+			default:
+				log.Printf("[WARN] ssh tunnel: error accepting local connection: %s", err)
+				return
+			}
+		}
+
+		go t.forward(localConn)
+	}
+}
+
+func (t *sshTunnel) forward(localConn net.Conn) {
+	defer localConn.Close()
+
+	remoteConn, err := t.client.Dial("tcp", t.remoteAddr)
+	if err != nil {
+		log.Printf("[WARN] ssh tunnel: error dialing remote %s: %s", t.remoteAddr, err)
+		return
+	}
+	defer remoteConn.Close()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		io.Copy(remoteConn, localConn)
+	}()
+	go func() {
+		defer wg.Done()
+		io.Copy(localConn, remoteConn)
+	}()
+	wg.Wait()
+}
+
+// Close stops accepting new connections and closes the underlying SSH
+// client. Called once by Client.Close() when the provider itself shuts
+// down; if refCount is still nonzero at that point, some acquirer never
+// released the tunnel, but Close tears it down regardless since the Client
+// it belongs to is going away either way.
+func (t *sshTunnel) Close() error {
+	t.stopOnce.Do(func() {
+		close(t.stopCh)
+	})
+
+	t.mu.Lock()
+	listener := t.localListener
+	client := t.client
+	t.mu.Unlock()
+
+	if listener != nil {
+		listener.Close()
+	}
+
+	if client != nil {
+		return client.Close()
+	}
+
+	return nil
+}
+
+func sshAuthMethods(config Config) ([]ssh.AuthMethod, error) {
+	var methods []ssh.AuthMethod
+
+	if config.SshPrivateKey != "" {
+		var signer ssh.Signer
+		var err error
+		if config.SshPrivateKeyPassphrase != "" {
+			signer, err = ssh.ParsePrivateKeyWithPassphrase([]byte(config.SshPrivateKey), []byte(config.SshPrivateKeyPassphrase))
+		} else {
+			signer, err = ssh.ParsePrivateKey([]byte(config.SshPrivateKey))
+		}
+		if err != nil {
+			return nil, errwrap.Wrapf("Error parsing ssh_tunnel private key: {{err}}", err)
+		}
+		methods = append(methods, ssh.PublicKeys(signer))
+	}
+
+	if config.SshPassword != "" {
+		methods = append(methods, ssh.Password(config.SshPassword))
+	}
+
+	if config.SshAgent {
+		if sshAgent, err := net.Dial("unix", os.Getenv("SSH_AUTH_SOCK")); err == nil {
+			agentClient := agent.NewClient(sshAgent)
+			methods = append(methods, ssh.PublicKeysCallback(agentSigners(agentClient, config.SshAgentIdentity)))
+		}
+	}
+
+	if len(methods) == 0 {
+		return nil, fmt.Errorf("no authentication method configured for the ssh tunnel: set bastion_password, bastion_private_key or agent = true")
+	}
+
+	return methods, nil
+}
+
+// agentSigners returns a Signers callback for ssh.PublicKeysCallback. When
+// identity is empty it simply delegates to the agent's own Signers method,
+// offering every identity the agent holds. When identity is set (as a
+// comment or an SHA256 key fingerprint, e.g. "SHA256:..."), only the
+// matching identity is offered, so a bastion that limits login attempts
+// doesn't see the rest of the agent's keys tried and rejected first.
+func agentSigners(agentClient agent.Agent, identity string) func() ([]ssh.Signer, error) {
+	if identity == "" {
+		return agentClient.Signers
+	}
+
+	return func() ([]ssh.Signer, error) {
+		keys, err := agentClient.List()
+		if err != nil {
+			return nil, errwrap.Wrapf("Error listing ssh-agent identities: {{err}}", err)
+		}
+		signers, err := agentClient.Signers()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, key := range keys {
+			if key.Comment != identity && ssh.FingerprintSHA256(key) != identity {
+				continue
+			}
+
+			for _, signer := range signers {
+				if bytes.Equal(signer.PublicKey().Marshal(), key.Marshal()) {
+					return []ssh.Signer{signer}, nil
+				}
+			}
+		}
+
+		return nil, fmt.Errorf("no ssh-agent identity matching agent_identity %q", identity)
+	}
+}
+
+// sshHostKeyCallback verifies the bastion host key against the known
+// bastion_host_key value, if one was supplied. Without one, the connection
+// falls back to insecurely accepting any host key, matching the historical
+// (unverified) behavior of this provider.
+func sshHostKeyCallback(config Config) (ssh.HostKeyCallback, error) {
+	if config.SshHostKey == "" {
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+
+	expectedKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(config.SshHostKey))
+	if err != nil {
+		return nil, errwrap.Wrapf("Error parsing bastion_host_key: {{err}}", err)
+	}
+
+	return ssh.FixedHostKey(expectedKey), nil
+}
@@ -0,0 +1,185 @@
+package postgresql
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+func TestSshHostKeyCallback_NoKeyConfigured(t *testing.T) {
+	cb, err := sshHostKeyCallback(Config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if cb == nil {
+		t.Fatal("expected a non-nil callback")
+	}
+}
+
+func TestSshHostKeyCallback_InvalidKey(t *testing.T) {
+	if _, err := sshHostKeyCallback(Config{SshHostKey: "not a key"}); err == nil {
+		t.Error("expected an error for an unparseable bastion_host_key, got nil")
+	}
+}
+
+func TestSshHostKeyCallback_ValidKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("could not generate RSA key: %s", err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatalf("could not build ssh signer: %s", err)
+	}
+	authorizedKey := string(ssh.MarshalAuthorizedKey(signer.PublicKey()))
+
+	cb, err := sshHostKeyCallback(Config{SshHostKey: authorizedKey})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if cb == nil {
+		t.Fatal("expected a non-nil callback")
+	}
+}
+
+func newTestTunnel(t *testing.T) *sshTunnel {
+	t.Helper()
+	return &sshTunnel{
+		remoteAddr: "postgres.internal:5432",
+		stopCh:     make(chan struct{}),
+	}
+}
+
+func TestSshTunnel_AcquireReleaseRefCounting(t *testing.T) {
+	tun := newTestTunnel(t)
+	defer tun.Close()
+
+	host1, port1, err := tun.Acquire()
+	if err != nil {
+		t.Fatalf("unexpected error on first Acquire: %s", err)
+	}
+	if tun.refCount != 1 {
+		t.Errorf("refCount = %d, want 1", tun.refCount)
+	}
+
+	host2, port2, err := tun.Acquire()
+	if err != nil {
+		t.Fatalf("unexpected error on second Acquire: %s", err)
+	}
+	if tun.refCount != 2 {
+		t.Errorf("refCount = %d, want 2", tun.refCount)
+	}
+	if host1 != host2 || port1 != port2 {
+		t.Errorf("concurrent acquirers got different local addresses: %s:%d vs %s:%d", host1, port1, host2, port2)
+	}
+
+	tun.Release()
+	if tun.refCount != 1 {
+		t.Errorf("refCount = %d after one Release, want 1", tun.refCount)
+	}
+	if tun.localListener == nil {
+		t.Error("tunnel should still be listening while an acquirer remains")
+	}
+
+	tun.Release()
+	if tun.refCount != 0 {
+		t.Errorf("refCount = %d after final Release, want 0", tun.refCount)
+	}
+	if tun.localListener == nil {
+		t.Error("Release reaching zero should not close the tunnel; only Close does")
+	}
+
+	// An extra, unbalanced Release must not drive refCount negative.
+	tun.Release()
+	if tun.refCount != 0 {
+		t.Errorf("refCount = %d after an unbalanced extra Release, want 0", tun.refCount)
+	}
+}
+
+// fakeAgent implements just enough of agent.Agent for TestAgentSigners; the
+// methods beyond List/Signers are never exercised by agentSigners.
+type fakeAgent struct {
+	keys    []*agent.Key
+	signers []ssh.Signer
+}
+
+func (f *fakeAgent) List() ([]*agent.Key, error)     { return f.keys, nil }
+func (f *fakeAgent) Signers() ([]ssh.Signer, error)  { return f.signers, nil }
+func (f *fakeAgent) Add(key agent.AddedKey) error    { return nil }
+func (f *fakeAgent) Remove(key ssh.PublicKey) error  { return nil }
+func (f *fakeAgent) RemoveAll() error                { return nil }
+func (f *fakeAgent) Lock(passphrase []byte) error    { return nil }
+func (f *fakeAgent) Unlock(passphrase []byte) error  { return nil }
+func (f *fakeAgent) Sign(key ssh.PublicKey, data []byte) (*ssh.Signature, error) {
+	return nil, nil
+}
+
+func newTestSigner(t *testing.T) ssh.Signer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("could not generate RSA key: %s", err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatalf("could not build ssh signer: %s", err)
+	}
+	return signer
+}
+
+func newTestAgentKey(t *testing.T, signer ssh.Signer, comment string) *agent.Key {
+	t.Helper()
+	pub := signer.PublicKey()
+	return &agent.Key{
+		Format:  pub.Type(),
+		Blob:    pub.Marshal(),
+		Comment: comment,
+	}
+}
+
+func TestAgentSigners_NoIdentityReturnsAll(t *testing.T) {
+	wantSigner := newTestSigner(t)
+	fake := &fakeAgent{signers: []ssh.Signer{wantSigner}}
+
+	signers, err := agentSigners(fake, "")()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(signers) != 1 || signers[0] != wantSigner {
+		t.Error("expected the agent's Signers() result to pass through unchanged")
+	}
+}
+
+func TestAgentSigners_MatchByComment(t *testing.T) {
+	wantSigner := newTestSigner(t)
+	otherSigner := newTestSigner(t)
+	fake := &fakeAgent{
+		keys: []*agent.Key{
+			newTestAgentKey(t, otherSigner, "other-key"),
+			newTestAgentKey(t, wantSigner, "deploy-key"),
+		},
+		signers: []ssh.Signer{otherSigner, wantSigner},
+	}
+
+	signers, err := agentSigners(fake, "deploy-key")()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(signers) != 1 || signers[0] != wantSigner {
+		t.Error("expected only the identity matching agent_identity to be offered")
+	}
+}
+
+func TestAgentSigners_NoMatch(t *testing.T) {
+	fake := &fakeAgent{
+		keys:    []*agent.Key{newTestAgentKey(t, newTestSigner(t), "other-key")},
+		signers: []ssh.Signer{},
+	}
+
+	if _, err := agentSigners(fake, "missing-key")(); err == nil {
+		t.Error("expected an error when no agent identity matches agent_identity, got nil")
+	}
+}
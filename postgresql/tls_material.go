@@ -0,0 +1,117 @@
+package postgresql
+
+import (
+	"crypto/x509" //nolint:staticcheck // DecryptPEMBlock is deprecated but still the only stdlib way to read a libpq-style encrypted PEM key
+	"encoding/pem"
+	"fmt"
+	"os"
+)
+
+// writeInlinePEMToTempFile writes PEM-encoded TLS material (a root CA
+// bundle, client certificate, or client key) to a private temp file and
+// returns its path. libpq's sslrootcert/sslcert/sslkey options only accept
+// file paths, so the *_inline variants need somewhere on disk to point at
+// for the lifetime of the connection.
+//
+// os.CreateTemp creates the file with 0600 permissions before anything is
+// written, so the key material is never briefly world-readable; the Chmod
+// below only re-asserts that mode.
+func writeInlinePEMToTempFile(prefix, pemContents string) (string, error) {
+	f, err := os.CreateTemp("", "postgresql-provider-"+prefix+"-*.pem")
+	if err != nil {
+		return "", fmt.Errorf("Error creating temp file for %s: %w", prefix, err)
+	}
+	defer f.Close()
+
+	if err := f.Chmod(0600); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("Error securing temp file for %s: %w", prefix, err)
+	}
+
+	if _, err := f.WriteString(pemContents); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("Error writing %s to temp file: %w", prefix, err)
+	}
+
+	return f.Name(), nil
+}
+
+// resolveSSLFilePath reconciles a plain file-path attribute (e.g.
+// sslrootcert) with its *_inline counterpart (e.g. sslrootcert_inline): the
+// inline content, if present, wins and is materialized to a temp file;
+// otherwise the explicit path (which may be empty) is used as-is.
+func resolveSSLFilePath(prefix, explicitPath, inlineContents string) (string, error) {
+	if inlineContents == "" {
+		return explicitPath, nil
+	}
+	return writeInlinePEMToTempFile(prefix, inlineContents)
+}
+
+// decryptPEMKey decrypts a PEM-encoded, passphrase-protected private key
+// (the classic "Proc-Type: 4,ENCRYPTED" PEM format libpq's sslpassword
+// option decrypts) and re-encodes it as a plain, unencrypted PEM block.
+// Neither lib/pq nor pgx/v5 understand sslpassword natively, since they
+// hand sslkey straight to crypto/tls, which can't read encrypted keys at
+// all; decrypting it ourselves before it reaches the driver is the only way
+// to honor sslpassword with either.
+//
+// Only the classic format is supported: x509.IsEncryptedPEMBlock can't see
+// the modern PKCS#8 "ENCRYPTED PRIVATE KEY" header (e.g. from `openssl
+// genpkey -aes256`) at all, so that case is called out explicitly below
+// rather than silently passed through as if it were already decrypted.
+func decryptPEMKey(keyPEM, password string) (string, error) {
+	block, _ := pem.Decode([]byte(keyPEM))
+	if block == nil {
+		return "", fmt.Errorf("sslpassword: could not parse PEM key material")
+	}
+	if block.Type == "ENCRYPTED PRIVATE KEY" {
+		return "", fmt.Errorf("sslpassword: PKCS#8 encrypted private keys are not supported; re-encrypt sslkey in the classic PEM format (e.g. `openssl rsa -aes256 -in key.pem -out key.pem`)")
+	}
+	if !x509.IsEncryptedPEMBlock(block) {
+		return keyPEM, nil
+	}
+
+	der, err := x509.DecryptPEMBlock(block, []byte(password))
+	if err != nil {
+		return "", fmt.Errorf("sslpassword: could not decrypt private key: %w", err)
+	}
+
+	decrypted := pem.EncodeToMemory(&pem.Block{Type: block.Type, Bytes: der})
+	return string(decrypted), nil
+}
+
+// resolveSSLKeyPath is resolveSSLFilePath for sslkey specifically: if
+// password is set, the resolved key (whether it came from sslkey or
+// sslkey_inline) is decrypted first and the plaintext key is what actually
+// gets written to disk, so sslpassword works the same way whether the
+// driver underneath is lib/pq or pgx/v5.
+func resolveSSLKeyPath(explicitPath, inlineContents, password string) (string, error) {
+	if password == "" {
+		return resolveSSLFilePath("sslkey", explicitPath, inlineContents)
+	}
+
+	keyPEM := inlineContents
+	if keyPEM == "" {
+		contents, err := os.ReadFile(explicitPath)
+		if err != nil {
+			return "", fmt.Errorf("sslpassword: could not read sslkey %q: %w", explicitPath, err)
+		}
+		keyPEM = string(contents)
+	}
+
+	decrypted, err := decryptPEMKey(keyPEM, password)
+	if err != nil {
+		return "", err
+	}
+	return writeInlinePEMToTempFile("sslkey", decrypted)
+}
+
+// validateSSLVerification ensures a root certificate has been supplied
+// (either as a path or inline) whenever sslmode demands that the server
+// certificate actually be verified.
+func validateSSLVerification(sslMode, sslRootCert string) error {
+	if (sslMode == "verify-ca" || sslMode == "verify-full") && sslRootCert == "" {
+		return fmt.Errorf("sslmode %q requires sslrootcert or sslrootcert_inline to be set", sslMode)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package postgresql
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509" //nolint:staticcheck // EncryptPEMBlock is deprecated but is the only stdlib way to build a fixture for decryptPEMKey
+	"encoding/pem"
+	"os"
+	"testing"
+)
+
+func generateEncryptedKeyPEM(t *testing.T, password string) string {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("could not generate RSA key: %s", err)
+	}
+
+	block, err := x509.EncryptPEMBlock( //nolint:staticcheck
+		rand.Reader,
+		"RSA PRIVATE KEY",
+		x509.MarshalPKCS1PrivateKey(key),
+		[]byte(password),
+		x509.PEMCipherAES256,
+	)
+	if err != nil {
+		t.Fatalf("could not encrypt PEM block: %s", err)
+	}
+
+	return string(pem.EncodeToMemory(block))
+}
+
+func TestDecryptPEMKey(t *testing.T) {
+	encrypted := generateEncryptedKeyPEM(t, "correct horse")
+
+	decrypted, err := decryptPEMKey(encrypted, "correct horse")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	block, _ := pem.Decode([]byte(decrypted))
+	if block == nil {
+		t.Fatal("decrypted key is not valid PEM")
+	}
+	if x509.IsEncryptedPEMBlock(block) { //nolint:staticcheck
+		t.Error("decrypted key is still encrypted")
+	}
+}
+
+func TestDecryptPEMKey_WrongPassword(t *testing.T) {
+	encrypted := generateEncryptedKeyPEM(t, "correct horse")
+
+	if _, err := decryptPEMKey(encrypted, "wrong password"); err == nil {
+		t.Error("expected an error decrypting with the wrong password, got nil")
+	}
+}
+
+func TestDecryptPEMKey_Unencrypted(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("could not generate RSA key: %s", err)
+	}
+	plain := string(pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	}))
+
+	got, err := decryptPEMKey(plain, "unused")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got != plain {
+		t.Error("decryptPEMKey should return an already-unencrypted key unchanged")
+	}
+}
+
+func TestResolveSSLKeyPath_NoPassword(t *testing.T) {
+	got, err := resolveSSLKeyPath("/etc/ssl/client.key", "", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got != "/etc/ssl/client.key" {
+		t.Errorf("resolveSSLKeyPath() = %q, want the explicit path unchanged", got)
+	}
+}
+
+func TestResolveSSLKeyPath_EncryptedInline(t *testing.T) {
+	encrypted := generateEncryptedKeyPEM(t, "correct horse")
+
+	path, err := resolveSSLKeyPath("", encrypted, "correct horse")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer os.Remove(path)
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("could not read resolved key file: %s", err)
+	}
+	block, _ := pem.Decode([]byte(contents))
+	if block == nil || x509.IsEncryptedPEMBlock(block) { //nolint:staticcheck
+		t.Error("resolved key file should contain a decrypted PEM block")
+	}
+}
@@ -0,0 +1,108 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+)
+
+// serializationFailureSQLState is the SQLSTATE Postgres returns when a
+// serializable (or repeatable read) transaction can't be committed because
+// of a conflicting concurrent transaction. See
+// https://www.postgresql.org/docs/current/errcodes-appendix.html.
+const serializationFailureSQLState = "40001"
+
+// cannotConnectNowSQLState is the SQLSTATE a connection attempt fails with
+// while Postgres is still starting up, recovering, or otherwise refusing new
+// connections (e.g. right after a failover). It's transient, so
+// startTransaction retries around it the same way it retries serialization
+// failures.
+const cannotConnectNowSQLState = "57P03"
+
+// sqlState extracts the SQLSTATE code from err, recognizing both the
+// lib/pq and pgx/v5 error types (the provider is mid-migration from
+// lib/pq to jackc/pgx/v5/stdlib, so either can surface depending on which
+// layer raised the error). Returns "" if err isn't a recognized Postgres
+// error.
+func sqlState(err error) string {
+	switch e := err.(type) {
+	case *pq.Error:
+		return string(e.Code)
+	case *pgconn.PgError:
+		return e.Code
+	default:
+		return ""
+	}
+}
+
+// isolationLevelFromString maps the default_isolation_level provider
+// attribute onto a database/sql isolation level, the same levels
+// sql.IsolationLevel understands.
+func isolationLevelFromString(level string) (sql.IsolationLevel, error) {
+	switch level {
+	case "", "read_committed":
+		return sql.LevelReadCommitted, nil
+	case "repeatable_read":
+		return sql.LevelRepeatableRead, nil
+	case "serializable":
+		return sql.LevelSerializable, nil
+	default:
+		return sql.LevelDefault, fmt.Errorf(
+			"default_isolation_level: unsupported value %q (must be one of read_committed, repeatable_read, serializable)",
+			level,
+		)
+	}
+}
+
+// isSerializationFailure reports whether err is a Postgres serialization
+// failure (SQLSTATE 40001), the error retry_on_serialization_failure exists
+// to retry around.
+func isSerializationFailure(err error) bool {
+	return sqlState(err) == serializationFailureSQLState
+}
+
+// isCannotConnectNow reports whether err is a Postgres "cannot connect now"
+// failure (SQLSTATE 57P03), the transient error withConnectRetry exists to
+// retry around.
+func isCannotConnectNow(err error) bool {
+	return sqlState(err) == cannotConnectNowSQLState
+}
+
+// withSerializationRetry runs fn, retrying with exponential backoff (plus
+// jitter, to avoid a thundering herd of retries all colliding again) up to
+// maxRetries times if fn fails with a serialization failure. maxRetries <= 0
+// disables retrying and fn is simply called once.
+func withSerializationRetry(maxRetries int, fn func() error) error {
+	return withRetry(maxRetries, isSerializationFailure, fn)
+}
+
+// withConnectRetry runs fn, retrying with the same exponential backoff as
+// withSerializationRetry up to maxRetries times if fn fails because Postgres
+// isn't accepting connections yet (SQLSTATE 57P03). startTransaction uses
+// this to ride out a brief unavailability window, e.g. while a failover is
+// still in progress, instead of failing the whole apply.
+func withConnectRetry(maxRetries int, fn func() error) error {
+	return withRetry(maxRetries, isCannotConnectNow, fn)
+}
+
+// withRetry runs fn, retrying with exponential backoff plus jitter up to
+// maxRetries times as long as retryable(err) is true. maxRetries <= 0
+// disables retrying and fn is simply called once.
+func withRetry(maxRetries int, retryable func(error) bool, fn func() error) error {
+	var err error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		err = fn()
+		if err == nil || !retryable(err) || attempt == maxRetries {
+			return err
+		}
+
+		backoff := time.Duration(1<<uint(attempt)) * 100 * time.Millisecond
+		backoff += time.Duration(rand.Int63n(int64(backoff) / 2))
+		time.Sleep(backoff)
+	}
+	return err
+}
@@ -0,0 +1,120 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+)
+
+func TestIsolationLevelFromString(t *testing.T) {
+	cases := []struct {
+		level       string
+		want        sql.IsolationLevel
+		expectError bool
+	}{
+		{"", sql.LevelReadCommitted, false},
+		{"read_committed", sql.LevelReadCommitted, false},
+		{"repeatable_read", sql.LevelRepeatableRead, false},
+		{"serializable", sql.LevelSerializable, false},
+		{"bogus", sql.LevelDefault, true},
+	}
+
+	for _, c := range cases {
+		got, err := isolationLevelFromString(c.level)
+		if c.expectError {
+			if err == nil {
+				t.Errorf("isolationLevelFromString(%q): expected error, got nil", c.level)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("isolationLevelFromString(%q): unexpected error: %s", c.level, err)
+		}
+		if got != c.want {
+			t.Errorf("isolationLevelFromString(%q) = %v, want %v", c.level, got, c.want)
+		}
+	}
+}
+
+func TestWithSerializationRetry(t *testing.T) {
+	serializationErr := &pq.Error{Code: serializationFailureSQLState}
+
+	attempts := 0
+	err := withSerializationRetry(3, func() error {
+		attempts++
+		if attempts < 3 {
+			return serializationErr
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+}
+
+func TestWithSerializationRetry_NonSerializationErrorNotRetried(t *testing.T) {
+	attempts := 0
+	wantErr := fmt.Errorf("some other failure")
+	err := withSerializationRetry(3, func() error {
+		attempts++
+		return wantErr
+	})
+	if err != wantErr {
+		t.Errorf("err = %v, want %v", err, wantErr)
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1 (should not retry non-serialization errors)", attempts)
+	}
+}
+
+func TestSQLState(t *testing.T) {
+	if got := sqlState(&pq.Error{Code: serializationFailureSQLState}); got != serializationFailureSQLState {
+		t.Errorf("sqlState(*pq.Error) = %q, want %q", got, serializationFailureSQLState)
+	}
+	if got := sqlState(&pgconn.PgError{Code: cannotConnectNowSQLState}); got != cannotConnectNowSQLState {
+		t.Errorf("sqlState(*pgconn.PgError) = %q, want %q", got, cannotConnectNowSQLState)
+	}
+	if got := sqlState(fmt.Errorf("boom")); got != "" {
+		t.Errorf("sqlState(unrecognized error) = %q, want \"\"", got)
+	}
+}
+
+func TestWithConnectRetry(t *testing.T) {
+	connectErr := &pgconn.PgError{Code: cannotConnectNowSQLState}
+
+	attempts := 0
+	err := withConnectRetry(3, func() error {
+		attempts++
+		if attempts < 3 {
+			return connectErr
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+}
+
+func TestWithConnectRetry_NonConnectErrorNotRetried(t *testing.T) {
+	attempts := 0
+	wantErr := fmt.Errorf("some other failure")
+	err := withConnectRetry(3, func() error {
+		attempts++
+		return wantErr
+	})
+	if err != wantErr {
+		t.Errorf("err = %v, want %v", err, wantErr)
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1 (should not retry non-connect errors)", attempts)
+	}
+}
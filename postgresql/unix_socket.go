@@ -0,0 +1,68 @@
+package postgresql
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// isUnixSocketHost reports whether host names a Unix-domain socket
+// directory rather than a TCP host, following libpq's own convention: a
+// leading "/" is an absolute socket directory, a leading "@" is an
+// abstract-namespace socket (Linux-only).
+func isUnixSocketHost(host string) bool {
+	return strings.HasPrefix(host, "/") || strings.HasPrefix(host, "@")
+}
+
+// socketConnString builds a libpq key/value connection string for a
+// Unix-domain socket host. It exists because the URL-based DSN builder
+// net/url-encodes the host component, which mangles socket paths like
+// "/var/run/postgresql" into something libpq can no longer parse; key/value
+// form passes the path through untouched.
+func socketConnString(host string, port int, params map[string]string) string {
+	values := map[string]string{"host": host}
+	if port != 0 {
+		values["port"] = strconv.Itoa(port)
+	}
+	for k, v := range params {
+		if v == "" {
+			continue
+		}
+		values[k] = v
+	}
+
+	// Sorted iteration keeps the output deterministic, which matters for
+	// tests and for anything that might hash or compare connection strings.
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(quoteLibpqValue(values[k]))
+	}
+	return b.String()
+}
+
+// quoteLibpqValue single-quotes a libpq key/value DSN value if it contains
+// characters (whitespace, quotes, backslashes) that would otherwise need
+// escaping, matching the quoting tokenizeKeyValueConnectionString expects to
+// be able to parse back.
+func quoteLibpqValue(v string) string {
+	if v == "" {
+		return "''"
+	}
+	if !strings.ContainsAny(v, " \t\r\n'\\") {
+		return v
+	}
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, `'`, `\'`)
+	return "'" + v + "'"
+}
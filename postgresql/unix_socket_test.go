@@ -0,0 +1,44 @@
+package postgresql
+
+import "testing"
+
+func TestIsUnixSocketHost(t *testing.T) {
+	cases := map[string]bool{
+		"/var/run/postgresql": true,
+		"@abstract-socket":    true,
+		"localhost":           false,
+		"db.example.com":      false,
+		"":                    false,
+	}
+	for host, want := range cases {
+		if got := isUnixSocketHost(host); got != want {
+			t.Errorf("isUnixSocketHost(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
+
+func TestSocketConnString(t *testing.T) {
+	got := socketConnString("/var/run/postgresql", 5432, map[string]string{
+		"dbname": "postgres",
+		"user":   "postgres",
+	})
+	want := "dbname=postgres host=/var/run/postgresql port=5432 user=postgres"
+	if got != want {
+		t.Errorf("socketConnString() = %q, want %q", got, want)
+	}
+}
+
+func TestQuoteLibpqValue(t *testing.T) {
+	cases := map[string]string{
+		"postgres":       "postgres",
+		"":                "''",
+		"has space":      "'has space'",
+		`back\slash`:     `'back\\slash'`,
+		"o'clock":        `'o\'clock'`,
+	}
+	for in, want := range cases {
+		if got := quoteLibpqValue(in); got != want {
+			t.Errorf("quoteLibpqValue(%q) = %q, want %q", in, got, want)
+		}
+	}
+}